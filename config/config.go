@@ -51,6 +51,9 @@ func validateGenConfig(cfg *GenConfig) error {
 		if res.Region == "" {
 			return fmt.Errorf("resource[%d]: region is required", i)
 		}
+		if res.MinResources < 0 {
+			return fmt.Errorf("resource[%d]: min_resources must not be negative", i)
+		}
 		if resourceNames[res.Name] {
 			return fmt.Errorf("resource[%d]: duplicate resource name: %s", i, res.Name)
 		}
@@ -72,7 +75,31 @@ func validateGenConfig(cfg *GenConfig) error {
 		if !resourceNames[out.Data.ResourceName] {
 			return fmt.Errorf("output[%d]: resource_name '%s' not found in resources", i, out.Data.ResourceName)
 		}
+		if out.MaxShrinkPercent != nil && (*out.MaxShrinkPercent < 0 || *out.MaxShrinkPercent > 100) {
+			return fmt.Errorf("output[%d]: max_shrink_percent must be between 0 and 100", i)
+		}
+		if err := validateHooks(out.OnChange); err != nil {
+			return fmt.Errorf("output[%d]: %w", i, err)
+		}
 	}
 
+	return validateHooks(cfg.OnChange)
+}
+
+// validateHooks validates on_change hook definitions
+func validateHooks(hooks []HookConfig) error {
+	for i, hook := range hooks {
+		if len(hook.Command) == 0 || hook.Command[0] == "" {
+			return fmt.Errorf("on_change[%d]: command is required", i)
+		}
+		if hook.Timeout < 0 {
+			return fmt.Errorf("on_change[%d]: timeout must not be negative", i)
+		}
+		switch hook.OnFailure {
+		case "", HookFailureAbort, HookFailureContinue:
+		default:
+			return fmt.Errorf("on_change[%d]: on_failure must be '%s' or '%s'", i, HookFailureAbort, HookFailureContinue)
+		}
+	}
 	return nil
 }
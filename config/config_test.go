@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -222,6 +223,192 @@ outputs:
 	})
 }
 
+func TestLoadGenConfig_Hooks(t *testing.T) {
+	t.Run("global and per-output hooks", func(t *testing.T) {
+		content := `resources:
+  - name: redis
+    type: elasticache_redis
+    region: ap-northeast-1
+
+outputs:
+  - template: templates/redis.yaml.tmpl
+    output_file: /tmp/redisdb.yaml
+    data:
+      resource_name: redis
+    on_change:
+      - command: ["datadog-agent", "check", "redisdb"]
+        timeout: 1m
+
+on_change:
+  - command: ["systemctl", "reload", "datadog-agent"]
+    on_failure: continue
+`
+		tmpfile := createTempFile(t, content)
+		defer os.Remove(tmpfile)
+
+		cfg, err := LoadGenConfig(tmpfile)
+		require.NoError(t, err)
+
+		require.Len(t, cfg.Outputs[0].OnChange, 1)
+		assert.Equal(t, []string{"datadog-agent", "check", "redisdb"}, cfg.Outputs[0].OnChange[0].Command)
+		assert.Equal(t, time.Minute, cfg.Outputs[0].OnChange[0].Timeout)
+		assert.Equal(t, "", cfg.Outputs[0].OnChange[0].OnFailure)
+
+		require.Len(t, cfg.OnChange, 1)
+		assert.Equal(t, []string{"systemctl", "reload", "datadog-agent"}, cfg.OnChange[0].Command)
+		assert.Equal(t, HookFailureContinue, cfg.OnChange[0].OnFailure)
+	})
+
+	t.Run("invalid hooks", func(t *testing.T) {
+		testCases := []struct {
+			name        string
+			hooks       string
+			expectedErr string
+		}{
+			{
+				name: "missing command",
+				hooks: `on_change:
+  - timeout: 10s
+`,
+				expectedErr: "on_change[0]: command is required",
+			},
+			{
+				name: "invalid on_failure",
+				hooks: `on_change:
+  - command: ["true"]
+    on_failure: retry
+`,
+				expectedErr: "on_failure must be 'abort' or 'continue'",
+			},
+			{
+				name: "negative timeout",
+				hooks: `on_change:
+  - command: ["true"]
+    timeout: -1s
+`,
+				expectedErr: "timeout must not be negative",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				content := `resources:
+  - name: test
+    type: test_type
+    region: us-east-1
+outputs:
+  - template: test.tmpl
+    output_file: /tmp/test.yaml
+    data:
+      resource_name: test
+` + tc.hooks
+				tmpfile := createTempFile(t, content)
+				defer os.Remove(tmpfile)
+
+				_, err := LoadGenConfig(tmpfile)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			})
+		}
+	})
+}
+
+func TestLoadGenConfig_Guards(t *testing.T) {
+	t.Run("guards are parsed", func(t *testing.T) {
+		content := `resources:
+  - name: redis
+    type: elasticache_redis
+    region: ap-northeast-1
+    min_resources: 3
+
+outputs:
+  - template: templates/redis.yaml.tmpl
+    output_file: /tmp/redisdb.yaml
+    max_shrink_percent: 25
+    data:
+      resource_name: redis
+`
+		tmpfile := createTempFile(t, content)
+		defer os.Remove(tmpfile)
+
+		cfg, err := LoadGenConfig(tmpfile)
+		require.NoError(t, err)
+		assert.Equal(t, 3, cfg.Resources[0].MinResources)
+		require.NotNil(t, cfg.Outputs[0].MaxShrinkPercent)
+		assert.Equal(t, 25.0, *cfg.Outputs[0].MaxShrinkPercent)
+	})
+
+	t.Run("guards are optional", func(t *testing.T) {
+		content := `resources:
+  - name: redis
+    type: elasticache_redis
+    region: ap-northeast-1
+outputs:
+  - template: templates/redis.yaml.tmpl
+    output_file: /tmp/redisdb.yaml
+    data:
+      resource_name: redis
+`
+		tmpfile := createTempFile(t, content)
+		defer os.Remove(tmpfile)
+
+		cfg, err := LoadGenConfig(tmpfile)
+		require.NoError(t, err)
+		assert.Equal(t, 0, cfg.Resources[0].MinResources)
+		assert.Nil(t, cfg.Outputs[0].MaxShrinkPercent)
+	})
+
+	t.Run("invalid guards", func(t *testing.T) {
+		testCases := []struct {
+			name        string
+			content     string
+			expectedErr string
+		}{
+			{
+				name: "negative min_resources",
+				content: `resources:
+  - name: test
+    type: test_type
+    region: us-east-1
+    min_resources: -1
+outputs:
+  - template: test.tmpl
+    output_file: /tmp/test.yaml
+    data:
+      resource_name: test
+`,
+				expectedErr: "min_resources must not be negative",
+			},
+			{
+				name: "max_shrink_percent out of range",
+				content: `resources:
+  - name: test
+    type: test_type
+    region: us-east-1
+outputs:
+  - template: test.tmpl
+    output_file: /tmp/test.yaml
+    max_shrink_percent: 150
+    data:
+      resource_name: test
+`,
+				expectedErr: "max_shrink_percent must be between 0 and 100",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				tmpfile := createTempFile(t, tc.content)
+				defer os.Remove(tmpfile)
+
+				_, err := LoadGenConfig(tmpfile)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+			})
+		}
+	})
+}
+
 func createTempFile(t *testing.T, content string) string {
 	tmpfile, err := os.CreateTemp("", "meta-config-*.yaml")
 	require.NoError(t, err)
@@ -1,27 +1,50 @@
 package config
 
+import "time"
+
 // GenConfig represents the entire generation configuration file
 type GenConfig struct {
 	Resources []ResourceConfig `yaml:"resources"`
 	Outputs   []OutputConfig   `yaml:"outputs"`
+	OnChange  []HookConfig     `yaml:"on_change"`
 }
 
 // ResourceConfig represents a resource definition
 type ResourceConfig struct {
-	Name    string                 `yaml:"name"`
-	Type    string                 `yaml:"type"`
-	Region  string                 `yaml:"region"`
-	Filters map[string]interface{} `yaml:"filters"`
+	Name         string                 `yaml:"name"`
+	Type         string                 `yaml:"type"`
+	Region       string                 `yaml:"region"`
+	Profile      string                 `yaml:"profile"`
+	RoleARN      string                 `yaml:"role_arn"`
+	Filters      map[string]interface{} `yaml:"filters"`
+	MinResources int                    `yaml:"min_resources"`
 }
 
 // OutputConfig represents an output definition
 type OutputConfig struct {
-	Template   string     `yaml:"template"`
-	OutputFile string     `yaml:"output_file"`
-	Data       OutputData `yaml:"data"`
+	Template         string       `yaml:"template"`
+	OutputFile       string       `yaml:"output_file"`
+	Data             OutputData   `yaml:"data"`
+	OnChange         []HookConfig `yaml:"on_change"`
+	MaxShrinkPercent *float64     `yaml:"max_shrink_percent"`
 }
 
 // OutputData represents data passed to templates
 type OutputData struct {
 	ResourceName string `yaml:"resource_name"`
 }
+
+// HookConfig represents a command executed when outputs change
+type HookConfig struct {
+	Command   []string      `yaml:"command"`
+	Timeout   time.Duration `yaml:"timeout"`
+	OnFailure string        `yaml:"on_failure"`
+}
+
+// Hook failure policies
+const (
+	// HookFailureAbort stops the remaining hooks and fails the run (default)
+	HookFailureAbort = "abort"
+	// HookFailureContinue logs the failure and runs the remaining hooks
+	HookFailureContinue = "continue"
+)
@@ -0,0 +1,72 @@
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrGuard is wrapped by every error returned when a safety guard refuses an output
+var ErrGuard = errors.New("safety guard triggered")
+
+// CheckMinResources returns an error when fewer than minResources resources were discovered
+func CheckMinResources(name string, count, minResources int) error {
+	if count < minResources {
+		return fmt.Errorf("%w: resource '%s' discovered %d resources, fewer than min_resources %d", ErrGuard, name, count, minResources)
+	}
+	return nil
+}
+
+// CheckShrink returns an error when content has drastically fewer instances than the
+// existing output at path. The number of entries in the top-level "instances" list
+// is compared, and shrinking by more than maxShrinkPercent is refused.
+// A missing or unparsable existing file is not treated as a violation, while
+// rendered content that cannot be parsed is.
+func CheckShrink(path string, content []byte, maxShrinkPercent float64) error {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read existing file '%s': %w", path, err)
+	}
+
+	before, err := CountInstances(current)
+	if err != nil || before == 0 {
+		return nil
+	}
+
+	// Replacing a populated check configuration with one that cannot be parsed
+	// is refused like any other drastic shrink, so -force can override it
+	after, err := CountInstances(content)
+	if err != nil {
+		return fmt.Errorf("%w: failed to count instances in rendered output for '%s': %w", ErrGuard, path, err)
+	}
+
+	if after >= before {
+		return nil
+	}
+
+	shrinkPercent := float64(before-after) / float64(before) * 100
+	if shrinkPercent > maxShrinkPercent {
+		return fmt.Errorf("%w: output '%s' would shrink from %d to %d instances (%.1f%%), more than max_shrink_percent %g",
+			ErrGuard, path, before, after, shrinkPercent, maxShrinkPercent)
+	}
+
+	return nil
+}
+
+// CountInstances returns the number of entries in the top-level "instances" list
+// of a Datadog check configuration
+func CountInstances(content []byte) (int, error) {
+	var doc struct {
+		Instances []interface{} `yaml:"instances"`
+	}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return 0, fmt.Errorf("failed to parse check configuration: %w", err)
+	}
+	return len(doc.Instances), nil
+}
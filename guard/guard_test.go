@@ -0,0 +1,101 @@
+package guard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renderInstances returns a check configuration with n instances
+func renderInstances(n int) []byte {
+	var b strings.Builder
+	b.WriteString("init_config:\n\ninstances:\n")
+	for i := 0; i < n; i++ {
+		b.WriteString("  - host: node.example.com\n    port: 6379\n")
+	}
+	return []byte(b.String())
+}
+
+func TestCheckMinResources(t *testing.T) {
+	t.Run("enough resources", func(t *testing.T) {
+		assert.NoError(t, CheckMinResources("redis", 3, 3))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		assert.NoError(t, CheckMinResources("redis", 0, 0))
+	})
+
+	t.Run("too few resources", func(t *testing.T) {
+		err := CheckMinResources("redis", 0, 1)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrGuard)
+		assert.Contains(t, err.Error(), "resource 'redis' discovered 0 resources, fewer than min_resources 1")
+	})
+}
+
+func TestCheckShrink(t *testing.T) {
+	t.Run("no existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		assert.NoError(t, CheckShrink(path, renderInstances(0), 0))
+	})
+
+	t.Run("growing output", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, renderInstances(2), 0644))
+		assert.NoError(t, CheckShrink(path, renderInstances(5), 0))
+	})
+
+	t.Run("shrink within limit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, renderInstances(10), 0644))
+		assert.NoError(t, CheckShrink(path, renderInstances(5), 50))
+	})
+
+	t.Run("shrink beyond limit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, renderInstances(10), 0644))
+
+		err := CheckShrink(path, renderInstances(4), 50)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrGuard)
+		assert.Contains(t, err.Error(), "would shrink from 10 to 4 instances (60.0%)")
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, renderInstances(3), 0644))
+
+		err := CheckShrink(path, renderInstances(0), 90)
+		assert.ErrorIs(t, err, ErrGuard)
+	})
+
+	t.Run("existing file is not a check configuration", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("[[[ not yaml"), 0644))
+		assert.NoError(t, CheckShrink(path, renderInstances(0), 0))
+	})
+
+	t.Run("rendered output is not yaml", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, renderInstances(3), 0644))
+
+		err := CheckShrink(path, []byte("[[[ not yaml"), 50)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrGuard)
+		assert.Contains(t, err.Error(), "failed to count instances")
+	})
+}
+
+func TestCountInstances(t *testing.T) {
+	count, err := CountInstances(renderInstances(3))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = CountInstances([]byte("init_config:\n\ninstances:\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/moepig/dd-conf-gen/config"
+)
+
+// DefaultTimeout is used for hooks that do not specify a timeout
+const DefaultTimeout = 30 * time.Second
+
+// waitDelay bounds how long to wait for output pipes after a hook is killed
+const waitDelay = 5 * time.Second
+
+// Run executes hooks sequentially.
+// A failing hook with the "abort" policy (the default) stops the remaining
+// hooks and its error is returned; with the "continue" policy the failure is
+// logged and the next hook runs.
+func Run(ctx context.Context, hooks []config.HookConfig) error {
+	var errs []error
+	for i, hook := range hooks {
+		err := runHook(ctx, hook)
+		if err == nil {
+			continue
+		}
+
+		err = fmt.Errorf("on_change hook %d (%s) failed: %w", i, strings.Join(hook.Command, " "), err)
+		if hook.OnFailure == config.HookFailureContinue {
+			slog.Warn("Hook failed, continuing", "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		return err
+	}
+
+	if len(errs) > 0 {
+		slog.Warn("Some hooks failed", "failed", len(errs), "total", len(hooks))
+	}
+	return nil
+}
+
+// runHook executes a single hook and logs its captured output
+func runHook(ctx context.Context, hook config.HookConfig) error {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = waitDelay
+
+	slog.Info("Running hook", "command", hook.Command, "timeout", timeout)
+	start := time.Now()
+	err := cmd.Run()
+	duration := time.Since(start)
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	attrs := []any{
+		"command", hook.Command,
+		"duration", duration,
+		"stdout", strings.TrimSpace(stdout.String()),
+		"stderr", strings.TrimSpace(stderr.String()),
+	}
+	if err != nil {
+		slog.Error("Hook failed", append(attrs, "error", err)...)
+		return err
+	}
+
+	slog.Info("Hook succeeded", attrs...)
+	return nil
+}
@@ -0,0 +1,79 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("hooks run in order", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.txt")
+		hooks := []config.HookConfig{
+			{Command: []string{"sh", "-c", "echo first >> " + out}},
+			{Command: []string{"sh", "-c", "echo second >> " + out}},
+		}
+
+		require.NoError(t, Run(context.Background(), hooks))
+
+		content, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\n", string(content))
+	})
+
+	t.Run("no hooks", func(t *testing.T) {
+		assert.NoError(t, Run(context.Background(), nil))
+	})
+
+	t.Run("failing hook aborts by default", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.txt")
+		hooks := []config.HookConfig{
+			{Command: []string{"sh", "-c", "echo broken >&2; exit 3"}},
+			{Command: []string{"sh", "-c", "echo second >> " + out}},
+		}
+
+		err := Run(context.Background(), hooks)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "on_change hook 0")
+		assert.Contains(t, err.Error(), "exit status 3")
+		assert.NoFileExists(t, out)
+	})
+
+	t.Run("failing hook with continue policy", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out.txt")
+		hooks := []config.HookConfig{
+			{Command: []string{"false"}, OnFailure: config.HookFailureContinue},
+			{Command: []string{"sh", "-c", "echo second >> " + out}},
+		}
+
+		require.NoError(t, Run(context.Background(), hooks))
+		assert.FileExists(t, out)
+	})
+
+	t.Run("command not found", func(t *testing.T) {
+		hooks := []config.HookConfig{
+			{Command: []string{"/nonexistent/command"}},
+		}
+
+		err := Run(context.Background(), hooks)
+		assert.Error(t, err)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		hooks := []config.HookConfig{
+			{Command: []string{"sleep", "10"}, Timeout: 100 * time.Millisecond},
+		}
+
+		start := time.Now()
+		err := Run(context.Background(), hooks)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out after 100ms")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
@@ -2,32 +2,154 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/moepig/dd-conf-gen/config"
+	"github.com/moepig/dd-conf-gen/guard"
+	"github.com/moepig/dd-conf-gen/hooks"
 	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/amazonmq"
+	"github.com/moepig/dd-conf-gen/providers/cloudmap"
+	"github.com/moepig/dd-conf-gen/providers/ec2"
+	"github.com/moepig/dd-conf-gen/providers/ecs"
 	"github.com/moepig/dd-conf-gen/providers/elasticache"
+	"github.com/moepig/dd-conf-gen/providers/elbv2"
+	"github.com/moepig/dd-conf-gen/providers/memorydb"
+	"github.com/moepig/dd-conf-gen/providers/msk"
+	"github.com/moepig/dd-conf-gen/providers/opensearch"
+	"github.com/moepig/dd-conf-gen/providers/rds"
+	"github.com/moepig/dd-conf-gen/providers/redshift"
 	"github.com/moepig/dd-conf-gen/renderer"
+	"github.com/moepig/dd-conf-gen/state"
+	"github.com/moepig/dd-conf-gen/writer"
 )
 
+// exitCodeChanged is returned in dry-run mode when at least one output would change
+const exitCodeChanged = 2
+
 func init() {
 	// Register providers
 	providers.Register(elasticache.NewProvider())
+	providers.Register(elasticache.NewMemcachedProvider())
+	providers.Register(elasticache.NewServerlessProvider())
+	providers.Register(rds.NewInstanceProvider())
+	providers.Register(rds.NewAuroraProvider())
+	providers.Register(rds.NewDocDBProvider())
+	providers.Register(memorydb.NewProvider())
+	providers.Register(msk.NewProvider())
+	providers.Register(opensearch.NewProvider())
+	providers.Register(amazonmq.NewProvider())
+	providers.Register(ec2.NewProvider())
+	providers.Register(elbv2.NewProvider())
+	providers.Register(ecs.NewProvider())
+	providers.Register(cloudmap.NewProvider())
+	providers.Register(redshift.NewProvider())
 }
 
 func main() {
+	// Dispatch subcommands
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validateMain(os.Args[2:]))
+	}
+
 	// Command line arguments
 	configPath := flag.String("config", "", "Path to generation configuration file")
 	logLevelStr := flag.String("log-level", "info", "Log level (debug, info, warn, error)")
+	dryRun := flag.Bool("dry-run", false, "Render outputs and print a unified diff instead of writing files (exit code 2 if anything would change)")
+	watchMode := flag.Bool("watch", false, "Keep running and regenerate outputs periodically (SIGHUP reloads the configuration)")
+	interval := flag.Duration("interval", 5*time.Minute, "Interval between regenerations in watch mode")
+	concurrency := flag.Int("concurrency", 4, "Maximum number of resources discovered in parallel")
+	force := flag.Bool("force", false, "Write outputs even when min_resources or max_shrink_percent guards are triggered")
+	stateFile := flag.String("state-file", "", "Path to the state file recording generated outputs and pending hooks (default: .<config file name>"+defaultStateFileSuffix+" next to the config file)")
+	prune := flag.Bool("prune", false, "Remove previously generated output files that are no longer configured")
+	quarantineDir := flag.String("quarantine-dir", "", "Move pruned files into this directory instead of deleting them")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [validate] [options]\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	// Parse log level
+	if err := setupLogger(*logLevelStr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// Validate config option
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -config option is required")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *concurrency < 1 {
+		fmt.Fprintln(os.Stderr, "Error: -concurrency must be at least 1")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	opts := runOptions{
+		DryRun:        *dryRun,
+		Concurrency:   *concurrency,
+		Force:         *force,
+		StateFile:     *stateFile,
+		Prune:         *prune,
+		QuarantineDir: *quarantineDir,
+	}
+	if opts.StateFile == "" {
+		opts.StateFile = defaultStateFile(*configPath)
+	}
+
+	if *watchMode {
+		if *dryRun {
+			fmt.Fprintln(os.Stderr, "Error: -watch cannot be combined with -dry-run")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *interval <= 0 {
+			fmt.Fprintln(os.Stderr, "Error: -interval must be greater than zero")
+			flag.Usage()
+			os.Exit(1)
+		}
+	}
+
+	// Cancel in-flight work on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *watchMode {
+		if err := watch(ctx, *configPath, opts, *interval); err != nil {
+			slog.Error("Application failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Run the application
+	changed, err := run(ctx, *configPath, opts)
+	if err != nil {
+		slog.Error("Application failed", "error", err)
+		os.Exit(1)
+	}
+
+	if *dryRun && changed {
+		os.Exit(exitCodeChanged)
+	}
+}
+
+// setupLogger parses the log level and installs the default slog logger
+func setupLogger(logLevelStr string) error {
 	var logLevel slog.Level
-	switch *logLevelStr {
+	switch logLevelStr {
 	case "debug":
 		logLevel = slog.LevelDebug
 	case "info":
@@ -37,9 +159,7 @@ func main() {
 	case "error":
 		logLevel = slog.LevelError
 	default:
-		fmt.Fprintf(os.Stderr, "Error: invalid log level '%s' (must be debug, info, warn, or error)\n", *logLevelStr)
-		flag.Usage()
-		os.Exit(1)
+		return fmt.Errorf("invalid log level '%s' (must be debug, info, warn, or error)", logLevelStr)
 	}
 
 	// Initialize slog logger
@@ -47,67 +167,232 @@ func main() {
 		Level: logLevel,
 	}))
 	slog.SetDefault(logger)
+	return nil
+}
 
-	// Validate config option
-	if *configPath == "" {
-		fmt.Fprintln(os.Stderr, "Error: -config option is required")
-		flag.Usage()
-		os.Exit(1)
-	}
-
-	ctx := context.Background()
+// runOptions controls how run handles rendered outputs
+type runOptions struct {
+	// DryRun prints a diff of each output instead of writing it
+	DryRun bool
+	// Concurrency is the maximum number of resources discovered in parallel
+	Concurrency int
+	// Force writes outputs even when a safety guard is triggered
+	Force bool
+	// StateFile records the output files generated by previous runs and their pending hooks
+	StateFile string
+	// Prune removes previously generated files that are no longer configured
+	Prune bool
+	// QuarantineDir receives pruned files instead of deleting them (optional)
+	QuarantineDir string
+}
 
-	// Run the application
-	if err := run(ctx, *configPath); err != nil {
-		slog.Error("Application failed", "error", err)
-		os.Exit(1)
+// run loads the generation configuration and runs the generation pipeline once.
+// It reports whether any output changed or, in dry-run mode, would change.
+func run(ctx context.Context, configPath string, opts runOptions) (bool, error) {
+	genCfg, err := loadConfig(configPath)
+	if err != nil {
+		return false, err
 	}
+
+	return generate(ctx, genCfg, configPath, opts)
 }
 
-func run(ctx context.Context, configPath string) error {
-	// Load generation configuration
+// loadConfig loads the generation configuration and validates the provider configurations
+// before any AWS API is called
+func loadConfig(configPath string) (*config.GenConfig, error) {
 	slog.Info("Loading generation configuration", "config_path", configPath)
 	genCfg, err := config.LoadGenConfig(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to load generation config: %w", err)
+		return nil, fmt.Errorf("failed to load generation config: %w", err)
+	}
+
+	if err := validateProviders(genCfg); err != nil {
+		return nil, err
+	}
+
+	return genCfg, nil
+}
+
+// generate discovers resources, renders templates and writes (or diffs) the outputs
+func generate(ctx context.Context, genCfg *config.GenConfig, configPath string, opts runOptions) (bool, error) {
+	manifest, err := loadState(configPath, opts)
+	if err != nil {
+		return false, err
+	}
+	if !opts.DryRun && (len(manifest.PendingHooks) > 0 || manifest.PendingGlobalHooks) {
+		slog.Warn("Hooks of a previous run did not succeed and will be retried",
+			"outputs", manifest.PendingHooks, "global", manifest.PendingGlobalHooks)
 	}
 
 	// Discover resources for each resource config
-	slog.Info("Discovering resources")
-	resourceMap := make(map[string][]providers.Resource)
+	resourceMap, err := discoverResources(ctx, genCfg.Resources, opts.Concurrency)
+	if err != nil {
+		return false, err
+	}
+
+	// Refuse to continue when a resource returned suspiciously few results
 	for _, resCfg := range genCfg.Resources {
-		slog.Info("Discovering resource",
-			"name", resCfg.Name,
-			"type", resCfg.Type,
-			"region", resCfg.Region)
+		minErr := guard.CheckMinResources(resCfg.Name, len(resourceMap[resCfg.Name]), resCfg.MinResources)
+		if err := checkGuard(minErr, opts.Force); err != nil {
+			return false, err
+		}
+	}
 
-		provider, err := providers.Get(resCfg.Type)
+	// Render every template and run every guard before anything is written,
+	// so that a refused output never leaves the others half applied
+	slog.Info("Generating output files")
+	rendered, err := renderOutputs(genCfg, configPath, resourceMap)
+	if err != nil {
+		return false, err
+	}
+
+	for _, out := range rendered {
+		if out.config.MaxShrinkPercent == nil {
+			continue
+		}
+		// Refuse to drastically shrink an existing output
+		shrinkErr := guard.CheckShrink(out.config.OutputFile, out.content, *out.config.MaxShrinkPercent)
+		if err := checkGuard(shrinkErr, opts.Force); err != nil {
+			return false, err
+		}
+	}
+
+	changed := false
+	statusCounts := make(map[writer.Status]int)
+
+	for _, out := range rendered {
+		outCfg := out.config
+
+		// In dry-run mode, show what would change and leave the file untouched
+		if opts.DryRun {
+			diff, outputChanged, err := writer.Diff(outCfg.OutputFile, out.content)
+			if err != nil {
+				return false, err
+			}
+			if outputChanged {
+				changed = true
+				fmt.Print(diff)
+			}
+			slog.Info("Dry run: output not written", "path", outCfg.OutputFile, "changed", outputChanged)
+			continue
+		}
+
+		// Write output file atomically, skipping it when the content is unchanged
+		status, err := writer.Write(outCfg.OutputFile, out.content, 0644)
 		if err != nil {
-			return fmt.Errorf("failed to get provider for resource '%s': %w", resCfg.Name, err)
+			// Keep the hooks of the outputs already written for the next run
+			err = fmt.Errorf("failed to write output file '%s': %w", outCfg.OutputFile, err)
+			return false, errors.Join(err, saveState(manifest, opts))
 		}
 
-		providerCfg := providers.ProviderConfig{
-			Region:  resCfg.Region,
-			Filters: resCfg.Filters,
+		statusCounts[status]++
+		slog.Info("Processed output file", "path", outCfg.OutputFile, "status", status)
+		if status == writer.StatusUnchanged {
+			continue
 		}
+		changed = true
+		manifest.PendingGlobalHooks = true
 
-		slog.Debug("Provider config", "region", providerCfg.Region, "filters", providerCfg.Filters)
+		// Record the per-output hooks of the changed file until they succeed
+		if len(outCfg.OnChange) > 0 {
+			path, err := filepath.Abs(outCfg.OutputFile)
+			if err != nil {
+				return false, fmt.Errorf("failed to resolve output file '%s': %w", outCfg.OutputFile, err)
+			}
+			manifest.PendingHooks = append(manifest.PendingHooks, path)
+		}
+	}
 
-		discoveredResources, err := provider.Discover(ctx, providerCfg)
+	// Remove outputs that were generated before but are no longer configured
+	pruned, err := reconcileManagedFiles(manifest, genCfg, opts)
+	if err != nil {
+		return false, err
+	}
+	if pruned {
+		changed = true
+		if !opts.DryRun {
+			manifest.PendingGlobalHooks = true
+		}
+	}
+
+	if opts.DryRun {
+		slog.Info("Done!")
+		return changed, nil
+	}
+
+	slog.Info("Output summary",
+		"created", statusCounts[writer.StatusCreated],
+		"updated", statusCounts[writer.StatusUpdated],
+		"unchanged", statusCounts[writer.StatusUnchanged])
+
+	// Run the hooks of changed outputs, and those that failed in previous runs.
+	// The state file records the hooks that did not succeed so that they are
+	// retried even when the outputs are unchanged next time.
+	hookErr := runPendingHooks(ctx, genCfg, manifest)
+	if err := saveState(manifest, opts); err != nil {
+		return false, errors.Join(hookErr, err)
+	}
+	if hookErr != nil {
+		return false, hookErr
+	}
+
+	slog.Info("Done!")
+	return changed, nil
+}
+
+// runPendingHooks runs the per-output hooks of the outputs recorded as pending in
+// the manifest, in output order, followed by the top-level hooks when they are
+// pending. Hooks that succeed are cleared from the manifest. A failure stops the
+// remaining hooks, which stay pending along with the failed ones.
+func runPendingHooks(ctx context.Context, genCfg *config.GenConfig, manifest *state.Manifest) error {
+	pending := manifest.PendingHooks
+	var remaining []string
+	var hookErr error
+
+	for _, outCfg := range genCfg.Outputs {
+		path, err := filepath.Abs(outCfg.OutputFile)
 		if err != nil {
-			return fmt.Errorf("failed to discover resources for '%s': %w", resCfg.Name, err)
+			return fmt.Errorf("failed to resolve output file '%s': %w", outCfg.OutputFile, err)
+		}
+		if !slices.Contains(pending, path) || len(outCfg.OnChange) == 0 {
+			continue
+		}
+		if hookErr != nil {
+			remaining = append(remaining, path)
+			continue
 		}
 
-		resourceMap[resCfg.Name] = discoveredResources
-		slog.Info("Found resources",
-			"name", resCfg.Name,
-			"count", len(discoveredResources))
-		slog.Debug("Resource details", "name", resCfg.Name, "resources", discoveredResources)
+		if err := hooks.Run(ctx, outCfg.OnChange); err != nil {
+			hookErr = fmt.Errorf("hook failed for '%s': %w", outCfg.OutputFile, err)
+			remaining = append(remaining, path)
+		}
 	}
 
-	// Render templates and write output files
-	slog.Info("Generating output files")
+	// Outputs that are no longer configured have no hooks left to run
+	manifest.PendingHooks = remaining
+	if hookErr != nil {
+		return hookErr
+	}
+
+	if manifest.PendingGlobalHooks {
+		if err := hooks.Run(ctx, genCfg.OnChange); err != nil {
+			return err
+		}
+		manifest.PendingGlobalHooks = false
+	}
+	return nil
+}
+
+// renderedOutput is the rendered content of an output definition
+type renderedOutput struct {
+	config  config.OutputConfig
+	content []byte
+}
+
+// renderOutputs renders the template of every output definition
+func renderOutputs(genCfg *config.GenConfig, configPath string, resourceMap map[string][]providers.Resource) ([]renderedOutput, error) {
 	rend := renderer.NewRenderer("")
+	rendered := make([]renderedOutput, 0, len(genCfg.Outputs))
 
 	for _, outCfg := range genCfg.Outputs {
 		slog.Info("Rendering template", "output_file", outCfg.OutputFile)
@@ -115,7 +400,7 @@ func run(ctx context.Context, configPath string) error {
 		// Get resources for this output
 		discoveredResources, ok := resourceMap[outCfg.Data.ResourceName]
 		if !ok {
-			return fmt.Errorf("resource '%s' not found for output '%s'", outCfg.Data.ResourceName, outCfg.OutputFile)
+			return nil, fmt.Errorf("resource '%s' not found for output '%s'", outCfg.Data.ResourceName, outCfg.OutputFile)
 		}
 
 		// Prepare template data
@@ -123,37 +408,90 @@ func run(ctx context.Context, configPath string) error {
 			Resources: discoveredResources,
 		}
 
-		// Resolve template path (relative to generation config file)
-		templatePath := outCfg.Template
-		if !filepath.IsAbs(templatePath) {
-			configDir := filepath.Dir(configPath)
-			templatePath = filepath.Join(configDir, templatePath)
-		}
-
 		// Render template
-		output, err := rend.Render(templatePath, templateData)
+		output, err := rend.Render(resolveTemplatePath(configPath, outCfg.Template), templateData)
 		if err != nil {
-			return fmt.Errorf("failed to render template for '%s': %w", outCfg.OutputFile, err)
+			return nil, fmt.Errorf("failed to render template for '%s': %w", outCfg.OutputFile, err)
 		}
 
 		slog.Debug("Rendered output", "output_file", outCfg.OutputFile, "content", string(output))
+		rendered = append(rendered, renderedOutput{config: outCfg, content: output})
+	}
 
-		// Create output directory if needed
-		outDir := filepath.Dir(outCfg.OutputFile)
-		if outDir != "" && outDir != "." {
-			if err := os.MkdirAll(outDir, 0755); err != nil {
-				return fmt.Errorf("failed to create output directory '%s': %w", outDir, err)
-			}
+	return rendered, nil
+}
+
+// discoverResources discovers the resources of every resource definition,
+// running at most concurrency discoveries in parallel
+func discoverResources(ctx context.Context, resources []config.ResourceConfig, concurrency int) (map[string][]providers.Resource, error) {
+	slog.Info("Discovering resources", "count", len(resources), "concurrency", concurrency)
+
+	results, err := providers.ParallelMap(ctx, resources, concurrency, func(ctx context.Context, resCfg config.ResourceConfig) ([]providers.Resource, error) {
+		slog.Info("Discovering resource",
+			"name", resCfg.Name,
+			"type", resCfg.Type,
+			"region", resCfg.Region)
+
+		provider, err := providers.Get(resCfg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get provider for resource '%s': %w", resCfg.Name, err)
 		}
 
-		// Write output file
-		if err := os.WriteFile(outCfg.OutputFile, output, 0644); err != nil {
-			return fmt.Errorf("failed to write output file '%s': %w", outCfg.OutputFile, err)
+		providerCfg := newProviderConfig(resCfg)
+
+		slog.Debug("Provider config", "region", providerCfg.Region, "filters", providerCfg.Filters)
+
+		discoveredResources, err := provider.Discover(ctx, providerCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover resources for '%s': %w", resCfg.Name, err)
 		}
 
-		slog.Info("Written output file", "path", outCfg.OutputFile)
+		slog.Info("Found resources",
+			"name", resCfg.Name,
+			"count", len(discoveredResources))
+		slog.Debug("Resource details", "name", resCfg.Name, "resources", discoveredResources)
+		return discoveredResources, nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	slog.Info("Done!")
-	return nil
+	resourceMap := make(map[string][]providers.Resource, len(resources))
+	for i, resCfg := range resources {
+		resourceMap[resCfg.Name] = results[i]
+	}
+	return resourceMap, nil
+}
+
+// checkGuard returns a guard violation as an error, or only logs it when force is set
+func checkGuard(err error, force bool) error {
+	if err == nil {
+		return nil
+	}
+	if force && errors.Is(err, guard.ErrGuard) {
+		slog.Warn("Ignoring safety guard because -force is set", "error", err)
+		return nil
+	}
+	if errors.Is(err, guard.ErrGuard) {
+		return fmt.Errorf("%w (use -force to write anyway)", err)
+	}
+	return err
+}
+
+// newProviderConfig builds the provider configuration for a resource definition
+func newProviderConfig(resCfg config.ResourceConfig) providers.ProviderConfig {
+	return providers.ProviderConfig{
+		Region:  resCfg.Region,
+		Profile: resCfg.Profile,
+		RoleARN: resCfg.RoleARN,
+		Filters: resCfg.Filters,
+	}
+}
+
+// resolveTemplatePath resolves a template path relative to the generation config file
+func resolveTemplatePath(configPath, templatePath string) string {
+	if filepath.IsAbs(templatePath) {
+		return templatePath
+	}
+	return filepath.Join(filepath.Dir(configPath), templatePath)
 }
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/moepig/dd-conf-gen/guard"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticProviderType is the type of a provider that returns filters.count resources
+const staticProviderType = "test_static"
+
+// staticProvider returns a fixed number of resources without calling any API
+type staticProvider struct{}
+
+func (p *staticProvider) Type() string {
+	return staticProviderType
+}
+
+func (p *staticProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	_, err := providers.IntFilter(cfg.Filters, "count")
+	return err
+}
+
+func (p *staticProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	count, _ := providers.IntFilter(cfg.Filters, "count")
+	resources := make([]providers.Resource, 0, count)
+	for i := 0; i < count; i++ {
+		resources = append(resources, providers.Resource{Host: fmt.Sprintf("host-%d", i), Port: 6379})
+	}
+	return resources, nil
+}
+
+func init() {
+	providers.Register(&staticProvider{})
+}
+
+// checkTemplate renders one check instance per resource
+const checkTemplate = `instances:
+{{- range .Resources }}
+  - host: {{ .Host }}
+    port: {{ .Port }}
+{{- end }}
+`
+
+// newGenerateFixture writes the check template and returns the config path in a temporary directory
+func newGenerateFixture(t *testing.T) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "check.yaml.tmpl"), []byte(checkTemplate), 0644))
+	return dir, filepath.Join(dir, "gen-config.yaml")
+}
+
+// staticResource returns a resource definition discovering count resources
+func staticResource(name string, count int) config.ResourceConfig {
+	return config.ResourceConfig{
+		Name:    name,
+		Type:    staticProviderType,
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"count": count},
+	}
+}
+
+// touchHook returns a hook that creates path
+func touchHook(path string) []config.HookConfig {
+	return []config.HookConfig{{Command: []string{"touch", path}}}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("guard refuses every output before anything is written", func(t *testing.T) {
+		dir, configPath := newGenerateFixture(t)
+		first := filepath.Join(dir, "first.yaml")
+		second := filepath.Join(dir, "second.yaml")
+		require.NoError(t, os.WriteFile(second, []byte("instances:\n  - host: a\n  - host: b\n  - host: c\n  - host: d\n"), 0644))
+		maxShrink := 50.0
+
+		genCfg := &config.GenConfig{
+			Resources: []config.ResourceConfig{staticResource("healthy", 2), staticResource("shrunk", 1)},
+			Outputs: []config.OutputConfig{
+				{Template: "check.yaml.tmpl", OutputFile: first, Data: config.OutputData{ResourceName: "healthy"}, OnChange: touchHook(filepath.Join(dir, "first.hook"))},
+				{Template: "check.yaml.tmpl", OutputFile: second, Data: config.OutputData{ResourceName: "shrunk"}, MaxShrinkPercent: &maxShrink},
+			},
+		}
+		opts := runOptions{Concurrency: 1, StateFile: defaultStateFile(configPath)}
+
+		_, err := generate(context.Background(), genCfg, configPath, opts)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, guard.ErrGuard)
+
+		assert.NoFileExists(t, first)
+		assert.NoFileExists(t, filepath.Join(dir, "first.hook"))
+		assert.NoFileExists(t, opts.StateFile)
+
+		// -force writes every output
+		opts.Force = true
+		changed, err := generate(context.Background(), genCfg, configPath, opts)
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.FileExists(t, first)
+		assert.FileExists(t, filepath.Join(dir, "first.hook"))
+	})
+
+	t.Run("dry run writes nothing and reports changes", func(t *testing.T) {
+		dir, configPath := newGenerateFixture(t)
+		output := filepath.Join(dir, "redisdb.yaml")
+		outputMarker := filepath.Join(dir, "output.hook")
+		globalMarker := filepath.Join(dir, "global.hook")
+
+		genCfg := &config.GenConfig{
+			Resources: []config.ResourceConfig{staticResource("redis", 2)},
+			Outputs: []config.OutputConfig{
+				{Template: "check.yaml.tmpl", OutputFile: output, Data: config.OutputData{ResourceName: "redis"}, OnChange: touchHook(outputMarker)},
+			},
+			OnChange: touchHook(globalMarker),
+		}
+		opts := runOptions{Concurrency: 1, DryRun: true, StateFile: defaultStateFile(configPath)}
+
+		changed, err := generate(context.Background(), genCfg, configPath, opts)
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		assert.NoFileExists(t, output)
+		assert.NoFileExists(t, outputMarker)
+		assert.NoFileExists(t, globalMarker)
+		assert.NoFileExists(t, opts.StateFile)
+	})
+}
+
+func TestGenerate_PendingHooks(t *testing.T) {
+	dir, configPath := newGenerateFixture(t)
+	output := filepath.Join(dir, "redisdb.yaml")
+	ready := filepath.Join(dir, "ready")
+	globalMarker := filepath.Join(dir, "global.hook")
+
+	// The per-output hook fails until the ready file exists
+	genCfg := &config.GenConfig{
+		Resources: []config.ResourceConfig{staticResource("redis", 2)},
+		Outputs: []config.OutputConfig{
+			{
+				Template:   "check.yaml.tmpl",
+				OutputFile: output,
+				Data:       config.OutputData{ResourceName: "redis"},
+				OnChange:   []config.HookConfig{{Command: []string{"test", "-f", ready}}},
+			},
+		},
+		OnChange: touchHook(globalMarker),
+	}
+	opts := runOptions{Concurrency: 1, StateFile: defaultStateFile(configPath)}
+
+	_, err := generate(context.Background(), genCfg, configPath, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hook failed for '"+output+"'")
+
+	// The output is written, and the failed and skipped hooks are recorded
+	assert.FileExists(t, output)
+	assert.NoFileExists(t, globalMarker)
+	manifest, err := state.Load(opts.StateFile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{output}, manifest.PendingHooks)
+	assert.True(t, manifest.PendingGlobalHooks)
+
+	// The next run retries the hooks although the output is unchanged
+	require.NoError(t, os.WriteFile(ready, nil, 0644))
+	changed, err := generate(context.Background(), genCfg, configPath, opts)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.FileExists(t, globalMarker)
+
+	manifest, err = state.Load(opts.StateFile)
+	require.NoError(t, err)
+	assert.Empty(t, manifest.PendingHooks)
+	assert.False(t, manifest.PendingGlobalHooks)
+
+	// Hooks do not run again once they have succeeded
+	require.NoError(t, os.Remove(globalMarker))
+	_, err = generate(context.Background(), genCfg, configPath, opts)
+	require.NoError(t, err)
+	assert.NoFileExists(t, globalMarker)
+}
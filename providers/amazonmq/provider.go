@@ -0,0 +1,297 @@
+package amazonmq
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mq"
+	mqtypes "github.com/aws/aws-sdk-go-v2/service/mq/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "amazonmq_broker"
+
+// engineTypeFilter restricts discovery to brokers of one engine
+const engineTypeFilter = "engine_type"
+
+// Engine types accepted in filters.engine_type
+const (
+	engineTypeActiveMQ = "activemq"
+	engineTypeRabbitMQ = "rabbitmq"
+)
+
+// defaultConsolePort is used when the console URL has no explicit port
+const defaultConsolePort = 443
+
+const (
+	// describeConcurrency is the maximum number of DescribeBroker calls in flight
+	describeConcurrency = 5
+	// listPageSize is the MaxResults value used when listing brokers
+	listPageSize = 100
+)
+
+// Provider implements the providers.Provider interface for Amazon MQ
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	MQ MQAPI
+}
+
+// MQAPI defines the Amazon MQ API interface
+type MQAPI interface {
+	ListBrokers(ctx context.Context, params *mq.ListBrokersInput, optFns ...func(*mq.Options)) (*mq.ListBrokersOutput, error)
+	DescribeBroker(ctx context.Context, params *mq.DescribeBrokerInput, optFns ...func(*mq.Options)) (*mq.DescribeBrokerOutput, error)
+}
+
+// NewProvider creates a new Amazon MQ provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new Amazon MQ provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		MQ: mq.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	engineType, err := providers.StringFilter(cfg.Filters, engineTypeFilter)
+	if err != nil {
+		return err
+	}
+	switch engineType {
+	case "", engineTypeActiveMQ, engineTypeRabbitMQ:
+		return nil
+	default:
+		return fmt.Errorf("filters.%s must be one of %s or %s", engineTypeFilter, engineTypeActiveMQ, engineTypeRabbitMQ)
+	}
+}
+
+// Discover retrieves Amazon MQ broker instances based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting Amazon MQ discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	engineType, _ := providers.StringFilter(cfg.Filters, engineTypeFilter)
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags, "engine_type", engineType)
+
+	// Brokers are listed first; tags are only returned by DescribeBroker
+	allBrokers, err := listBrokers(ctx, clients.MQ)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []mqtypes.BrokerSummary
+	for _, summary := range allBrokers {
+		if engineType != "" && !strings.EqualFold(string(summary.EngineType), engineType) {
+			continue
+		}
+		switch summary.BrokerState {
+		case mqtypes.BrokerStateCreationInProgress, mqtypes.BrokerStateCreationFailed, mqtypes.BrokerStateDeletionInProgress:
+			slog.Debug("Skipping Amazon MQ broker",
+				"broker_name", aws.ToString(summary.BrokerName),
+				"state", summary.BrokerState)
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if len(summaries) == 0 {
+		slog.Info("No Amazon MQ brokers found")
+		return []providers.Resource{}, nil
+	}
+
+	// Describe brokers in parallel; results keep the order of the list
+	brokers, err := providers.ParallelMap(ctx, summaries, describeConcurrency, func(ctx context.Context, summary mqtypes.BrokerSummary) (*mq.DescribeBrokerOutput, error) {
+		return describeBroker(ctx, clients.MQ, aws.ToString(summary.BrokerId))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for _, broker := range brokers {
+		if !awsutil.MatchTags(broker.Tags, tags) {
+			continue
+		}
+
+		instances := extractBrokerInstances(broker)
+		slog.Debug("Extracted instances from broker",
+			"broker_name", aws.ToString(broker.BrokerName),
+			"instances_count", len(instances))
+		result = append(result, instances...)
+	}
+
+	slog.Info("Amazon MQ discovery completed", "total_instances", len(result))
+	return result, nil
+}
+
+// listBrokers lists all brokers in the region, following NextToken
+func listBrokers(ctx context.Context, client MQAPI) ([]mqtypes.BrokerSummary, error) {
+	var result []mqtypes.BrokerSummary
+	var nextToken *string
+
+	for {
+		input := &mq.ListBrokersInput{
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *mq.ListBrokersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListBrokers API call: %v", r)
+				}
+			}()
+			resp, err = client.ListBrokers(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Amazon MQ brokers: %w", err)
+		}
+		result = append(result, resp.BrokerSummaries...)
+
+		if aws.ToString(resp.NextToken) == "" {
+			return result, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// describeBroker returns the details of a broker, including its instances and tags
+func describeBroker(ctx context.Context, client MQAPI, brokerID string) (*mq.DescribeBrokerOutput, error) {
+	// Catch panic and convert to error
+	var resp *mq.DescribeBrokerOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during DescribeBroker API call: %v", r)
+			}
+		}()
+		resp, err = client.DescribeBroker(ctx, &mq.DescribeBrokerInput{
+			BrokerId: aws.String(brokerID),
+		})
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Amazon MQ broker %s: %w", brokerID, err)
+	}
+	return resp, nil
+}
+
+// extractBrokerInstances returns a resource per broker instance. Host and Port are
+// taken from the instance console URL, which is the web console for ActiveMQ and
+// the management API for RabbitMQ.
+func extractBrokerInstances(broker *mq.DescribeBrokerOutput) []providers.Resource {
+	brokerName := aws.ToString(broker.BrokerName)
+
+	var result []providers.Resource
+	for i, instance := range broker.BrokerInstances {
+		consoleURL := aws.ToString(instance.ConsoleURL)
+		host, port, err := parseConsoleURL(consoleURL)
+		if err != nil {
+			slog.Warn("Broker instance has no valid console URL",
+				"broker_name", brokerName,
+				"console_url", consoleURL,
+				"error", err)
+			continue
+		}
+
+		endpoints := make(map[string]string, len(instance.Endpoints))
+		for _, endpoint := range instance.Endpoints {
+			u, err := url.Parse(endpoint)
+			if err != nil || u.Scheme == "" {
+				slog.Warn("Skipping invalid broker endpoint", "broker_name", brokerName, "endpoint", endpoint)
+				continue
+			}
+			endpoints[u.Scheme] = endpoint
+		}
+
+		slog.Debug("Extracted broker instance",
+			"host", host,
+			"port", port,
+			"broker_name", brokerName,
+			"instance_index", i)
+
+		result = append(result, providers.Resource{
+			Host: host,
+			Port: port,
+			Tags: broker.Tags,
+			Metadata: map[string]interface{}{
+				"BrokerName":       brokerName,
+				"BrokerID":         aws.ToString(broker.BrokerId),
+				"BrokerArn":        aws.ToString(broker.BrokerArn),
+				"InstanceIndex":    i,
+				"ConsoleURL":       consoleURL,
+				"IPAddress":        aws.ToString(instance.IpAddress),
+				"Endpoints":        endpoints,
+				"EngineType":       string(broker.EngineType),
+				"EngineVersion":    aws.ToString(broker.EngineVersion),
+				"DeploymentMode":   string(broker.DeploymentMode),
+				"HostInstanceType": aws.ToString(broker.HostInstanceType),
+			},
+		})
+	}
+	return result
+}
+
+// parseConsoleURL returns the host and port of a console URL
+func parseConsoleURL(consoleURL string) (string, int, error) {
+	u, err := url.Parse(consoleURL)
+	if err != nil {
+		return "", 0, err
+	}
+	if u.Hostname() == "" {
+		return "", 0, fmt.Errorf("console URL %q has no host", consoleURL)
+	}
+	if u.Port() == "" {
+		return u.Hostname(), defaultConsolePort, nil
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port in console URL %q: %w", consoleURL, err)
+	}
+	return u.Hostname(), port, nil
+}
@@ -0,0 +1,288 @@
+package amazonmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/mq"
+	mqtypes "github.com/aws/aws-sdk-go-v2/service/mq/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMQClient is a mock implementation of MQAPI
+type MockMQClient struct {
+	mock.Mock
+}
+
+func (m *MockMQClient) ListBrokers(ctx context.Context, params *mq.ListBrokersInput, optFns ...func(*mq.Options)) (*mq.ListBrokersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mq.ListBrokersOutput), args.Error(1)
+}
+
+func (m *MockMQClient) DescribeBroker(ctx context.Context, params *mq.DescribeBrokerInput, optFns ...func(*mq.Options)) (*mq.DescribeBrokerOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*mq.DescribeBrokerOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client MQAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{MQ: client}, nil
+	})
+}
+
+// newBrokerSummary returns a running broker summary
+func newBrokerSummary(id string, engineType mqtypes.EngineType) mqtypes.BrokerSummary {
+	return mqtypes.BrokerSummary{
+		BrokerId:    aws.String(id),
+		BrokerName:  aws.String(id),
+		BrokerState: mqtypes.BrokerStateRunning,
+		EngineType:  engineType,
+	}
+}
+
+// newActiveMQBroker returns an active/standby ActiveMQ broker with two instances
+func newActiveMQBroker(id string, tags map[string]string) *mq.DescribeBrokerOutput {
+	instance := func(n string) mqtypes.BrokerInstance {
+		host := "b-" + id + "-" + n + ".mq.ap-northeast-1.amazonaws.com"
+		return mqtypes.BrokerInstance{
+			ConsoleURL: aws.String("https://" + host + ":8162"),
+			IpAddress:  aws.String("10.0.0." + n),
+			Endpoints: []string{
+				"ssl://" + host + ":61617",
+				"amqp+ssl://" + host + ":5671",
+				"stomp+ssl://" + host + ":61614",
+				"mqtt+ssl://" + host + ":8883",
+				"wss://" + host + ":61619",
+			},
+		}
+	}
+
+	return &mq.DescribeBrokerOutput{
+		BrokerId:         aws.String(id),
+		BrokerName:       aws.String(id),
+		BrokerArn:        aws.String("arn:aws:mq:ap-northeast-1:123456789012:broker:" + id + ":" + id),
+		BrokerState:      mqtypes.BrokerStateRunning,
+		BrokerInstances:  []mqtypes.BrokerInstance{instance("1"), instance("2")},
+		EngineType:       mqtypes.EngineTypeActivemq,
+		EngineVersion:    aws.String("5.18"),
+		DeploymentMode:   mqtypes.DeploymentModeActiveStandbyMultiAz,
+		HostInstanceType: aws.String("mq.m5.large"),
+		Tags:             tags,
+	}
+}
+
+// newRabbitMQBroker returns a single-instance RabbitMQ broker
+func newRabbitMQBroker(id string, tags map[string]string) *mq.DescribeBrokerOutput {
+	host := "b-" + id + ".mq.ap-northeast-1.amazonaws.com"
+	return &mq.DescribeBrokerOutput{
+		BrokerId:   aws.String(id),
+		BrokerName: aws.String(id),
+		BrokerArn:  aws.String("arn:aws:mq:ap-northeast-1:123456789012:broker:" + id + ":" + id),
+		BrokerInstances: []mqtypes.BrokerInstance{
+			{
+				ConsoleURL: aws.String("https://" + host),
+				Endpoints:  []string{"amqps://" + host + ":5671"},
+			},
+		},
+		EngineType:       mqtypes.EngineTypeRabbitmq,
+		EngineVersion:    aws.String("3.13"),
+		DeploymentMode:   mqtypes.DeploymentModeSingleInstance,
+		HostInstanceType: aws.String("mq.m7g.large"),
+		Tags:             tags,
+	}
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "amazonmq_broker", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"}))
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"engine_type": "rabbitmq"},
+	}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"engine_type": "kafka"},
+	})
+	assert.EqualError(t, err, "filters.engine_type must be one of activemq or rabbitmq")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"engine_type": true},
+	})
+	assert.EqualError(t, err, "filters.engine_type must be a string")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockMQ := new(MockMQClient)
+		provider := newTestProvider(mockMQ)
+
+		mockMQ.On("ListBrokers", mock.Anything, mock.MatchedBy(func(input *mq.ListBrokersInput) bool {
+			return input.NextToken == nil
+		}), mock.Anything).Return(&mq.ListBrokersOutput{
+			BrokerSummaries: []mqtypes.BrokerSummary{newBrokerSummary("orders", mqtypes.EngineTypeActivemq)},
+			NextToken:       aws.String("next"),
+		}, nil).Once()
+		mockMQ.On("ListBrokers", mock.Anything, mock.MatchedBy(func(input *mq.ListBrokersInput) bool {
+			return aws.ToString(input.NextToken) == "next"
+		}), mock.Anything).Return(&mq.ListBrokersOutput{
+			BrokerSummaries: []mqtypes.BrokerSummary{
+				newBrokerSummary("staging-events", mqtypes.EngineTypeRabbitmq),
+				newBrokerSummary("events", mqtypes.EngineTypeRabbitmq),
+			},
+		}, nil).Once()
+
+		mockMQ.On("DescribeBroker", mock.Anything, &mq.DescribeBrokerInput{BrokerId: aws.String("orders")}, mock.Anything).
+			Return(newActiveMQBroker("orders", map[string]string{"Environment": "production", "Team": "commerce"}), nil)
+		mockMQ.On("DescribeBroker", mock.Anything, &mq.DescribeBrokerInput{BrokerId: aws.String("staging-events")}, mock.Anything).
+			Return(newRabbitMQBroker("staging-events", map[string]string{"Environment": "staging"}), nil)
+		mockMQ.On("DescribeBroker", mock.Anything, &mq.DescribeBrokerInput{BrokerId: aws.String("events")}, mock.Anything).
+			Return(newRabbitMQBroker("events", map[string]string{"Environment": "production"}), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"Environment": "production"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		resource := result[0]
+		assert.Equal(t, "b-orders-1.mq.ap-northeast-1.amazonaws.com", resource.Host)
+		assert.Equal(t, 8162, resource.Port)
+		assert.Equal(t, "commerce", resource.Tags["Team"])
+		assert.Equal(t, "orders", resource.Metadata["BrokerName"])
+		assert.Equal(t, "orders", resource.Metadata["BrokerID"])
+		assert.Equal(t, "arn:aws:mq:ap-northeast-1:123456789012:broker:orders:orders", resource.Metadata["BrokerArn"])
+		assert.Equal(t, 0, resource.Metadata["InstanceIndex"])
+		assert.Equal(t, "https://b-orders-1.mq.ap-northeast-1.amazonaws.com:8162", resource.Metadata["ConsoleURL"])
+		assert.Equal(t, "10.0.0.1", resource.Metadata["IPAddress"])
+		assert.Equal(t, map[string]string{
+			"ssl":       "ssl://b-orders-1.mq.ap-northeast-1.amazonaws.com:61617",
+			"amqp+ssl":  "amqp+ssl://b-orders-1.mq.ap-northeast-1.amazonaws.com:5671",
+			"stomp+ssl": "stomp+ssl://b-orders-1.mq.ap-northeast-1.amazonaws.com:61614",
+			"mqtt+ssl":  "mqtt+ssl://b-orders-1.mq.ap-northeast-1.amazonaws.com:8883",
+			"wss":       "wss://b-orders-1.mq.ap-northeast-1.amazonaws.com:61619",
+		}, resource.Metadata["Endpoints"])
+		assert.Equal(t, "ACTIVEMQ", resource.Metadata["EngineType"])
+		assert.Equal(t, "5.18", resource.Metadata["EngineVersion"])
+		assert.Equal(t, "ACTIVE_STANDBY_MULTI_AZ", resource.Metadata["DeploymentMode"])
+		assert.Equal(t, "mq.m5.large", resource.Metadata["HostInstanceType"])
+
+		assert.Equal(t, "b-orders-2.mq.ap-northeast-1.amazonaws.com", result[1].Host)
+		assert.Equal(t, 1, result[1].Metadata["InstanceIndex"])
+
+		resource = result[2]
+		assert.Equal(t, "b-events.mq.ap-northeast-1.amazonaws.com", resource.Host)
+		assert.Equal(t, 443, resource.Port)
+		assert.Equal(t, "RABBITMQ", resource.Metadata["EngineType"])
+		assert.Equal(t, "SINGLE_INSTANCE", resource.Metadata["DeploymentMode"])
+		assert.Equal(t, map[string]string{"amqps": "amqps://b-events.mq.ap-northeast-1.amazonaws.com:5671"}, resource.Metadata["Endpoints"])
+
+		mockMQ.AssertExpectations(t)
+	})
+
+	t.Run("engine type filter", func(t *testing.T) {
+		mockMQ := new(MockMQClient)
+		provider := newTestProvider(mockMQ)
+
+		mockMQ.On("ListBrokers", mock.Anything, mock.Anything, mock.Anything).Return(&mq.ListBrokersOutput{
+			BrokerSummaries: []mqtypes.BrokerSummary{
+				newBrokerSummary("orders", mqtypes.EngineTypeActivemq),
+				newBrokerSummary("events", mqtypes.EngineTypeRabbitmq),
+			},
+		}, nil)
+		mockMQ.On("DescribeBroker", mock.Anything, &mq.DescribeBrokerInput{BrokerId: aws.String("events")}, mock.Anything).
+			Return(newRabbitMQBroker("events", nil), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"engine_type": "rabbitmq"},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "events", result[0].Metadata["BrokerName"])
+		mockMQ.AssertNotCalled(t, "DescribeBroker", mock.Anything, &mq.DescribeBrokerInput{BrokerId: aws.String("orders")}, mock.Anything)
+	})
+
+	t.Run("skips brokers being created or deleted", func(t *testing.T) {
+		mockMQ := new(MockMQClient)
+		provider := newTestProvider(mockMQ)
+
+		creating := newBrokerSummary("creating", mqtypes.EngineTypeRabbitmq)
+		creating.BrokerState = mqtypes.BrokerStateCreationInProgress
+		deleting := newBrokerSummary("deleting", mqtypes.EngineTypeRabbitmq)
+		deleting.BrokerState = mqtypes.BrokerStateDeletionInProgress
+
+		mockMQ.On("ListBrokers", mock.Anything, mock.Anything, mock.Anything).Return(&mq.ListBrokersOutput{
+			BrokerSummaries: []mqtypes.BrokerSummary{creating, deleting},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockMQ.AssertNotCalled(t, "DescribeBroker", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		mockMQ := new(MockMQClient)
+		provider := newTestProvider(mockMQ)
+
+		mockMQ.On("ListBrokers", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list Amazon MQ brokers")
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockMQ := new(MockMQClient)
+		provider := newTestProvider(mockMQ)
+
+		mockMQ.On("ListBrokers", mock.Anything, mock.Anything, mock.Anything).Return(&mq.ListBrokersOutput{
+			BrokerSummaries: []mqtypes.BrokerSummary{newBrokerSummary("events", mqtypes.EngineTypeRabbitmq)},
+		}, nil)
+		mockMQ.On("DescribeBroker", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe Amazon MQ broker events")
+	})
+}
+
+func TestParseConsoleURL(t *testing.T) {
+	host, port, err := parseConsoleURL("https://b-1234-1.mq.ap-northeast-1.amazonaws.com:8162")
+	require.NoError(t, err)
+	assert.Equal(t, "b-1234-1.mq.ap-northeast-1.amazonaws.com", host)
+	assert.Equal(t, 8162, port)
+
+	host, port, err = parseConsoleURL("https://b-1234.mq.ap-northeast-1.amazonaws.com")
+	require.NoError(t, err)
+	assert.Equal(t, "b-1234.mq.ap-northeast-1.amazonaws.com", host)
+	assert.Equal(t, 443, port)
+
+	_, _, err = parseConsoleURL("")
+	assert.Error(t, err)
+}
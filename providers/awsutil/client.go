@@ -0,0 +1,123 @@
+package awsutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/moepig/dd-conf-gen/providers"
+)
+
+// ClientKey identifies the AWS region and credentials a set of clients is bound to
+type ClientKey struct {
+	Region  string
+	Profile string
+	RoleARN string
+}
+
+// KeyFor returns the client key for a provider configuration
+func KeyFor(cfg providers.ProviderConfig) ClientKey {
+	return ClientKey{
+		Region:  cfg.Region,
+		Profile: cfg.Profile,
+		RoleARN: cfg.RoleARN,
+	}
+}
+
+// LoadConfig loads the AWS configuration for a client key.
+// The shared config profile is used when set, and the role is assumed
+// through STS when a role ARN is given.
+func LoadConfig(ctx context.Context, key ClientKey) (aws.Config, error) {
+	slog.Debug("Loading AWS configuration", "region", key.Region, "profile", key.Profile, "role_arn", key.RoleARN)
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(key.Region),
+	}
+	if key.Profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(key.Profile))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w (check AWS credentials and configuration)", err)
+	}
+
+	if key.RoleARN != "" {
+		stsClient := sts.NewFromConfig(awsCfg)
+		awsCfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient, key.RoleARN))
+	}
+
+	return awsCfg, nil
+}
+
+// ClientFactory returns the AWS clients to use for a provider configuration.
+// Clients must be bound to the region and credentials of the configuration.
+type ClientFactory[T any] func(ctx context.Context, cfg providers.ProviderConfig) (T, error)
+
+// NewClientFactory returns a ClientFactory that creates clients with newClients
+// once per region, profile and role and reuses them across discoveries
+func NewClientFactory[T any](newClients func(aws.Config) T) ClientFactory[T] {
+	return NewClientCache(newClients).Get
+}
+
+// ClientCache creates AWS clients once per client key and reuses them,
+// so that resources in different regions or accounts never share clients
+type ClientCache[T any] struct {
+	mu         sync.Mutex
+	entries    map[ClientKey]*clientEntry[T]
+	newClients func(aws.Config) T
+	loadConfig func(context.Context, ClientKey) (aws.Config, error)
+}
+
+// clientEntry holds the clients of one client key. Its lock is held while the
+// AWS configuration is loaded, so that different keys load in parallel.
+type clientEntry[T any] struct {
+	mu      sync.Mutex
+	clients T
+	loaded  bool
+}
+
+// NewClientCache creates a ClientCache that builds clients with newClients
+func NewClientCache[T any](newClients func(aws.Config) T) *ClientCache[T] {
+	return &ClientCache[T]{
+		entries:    make(map[ClientKey]*clientEntry[T]),
+		newClients: newClients,
+		loadConfig: LoadConfig,
+	}
+}
+
+// Get returns the clients for a provider configuration, creating them on first use
+func (c *ClientCache[T]) Get(ctx context.Context, cfg providers.ProviderConfig) (T, error) {
+	key := KeyFor(cfg)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &clientEntry[T]{}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.loaded {
+		return entry.clients, nil
+	}
+
+	// A failed load is not cached so that the next call retries
+	awsCfg, err := c.loadConfig(ctx, key)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	entry.clients = c.newClients(awsCfg)
+	entry.loaded = true
+	return entry.clients, nil
+}
@@ -0,0 +1,188 @@
+package awsutil
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClients struct {
+	region string
+}
+
+func newTestCache(loaded *[]ClientKey) *ClientCache[*testClients] {
+	cache := NewClientCache(func(awsCfg aws.Config) *testClients {
+		return &testClients{region: awsCfg.Region}
+	})
+	cache.loadConfig = func(ctx context.Context, key ClientKey) (aws.Config, error) {
+		*loaded = append(*loaded, key)
+		return aws.Config{Region: key.Region}, nil
+	}
+	return cache
+}
+
+func TestKeyFor(t *testing.T) {
+	key := KeyFor(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Profile: "prod",
+		RoleARN: "arn:aws:iam::123456789012:role/dd-conf-gen",
+		Filters: map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}},
+	})
+
+	assert.Equal(t, ClientKey{
+		Region:  "ap-northeast-1",
+		Profile: "prod",
+		RoleARN: "arn:aws:iam::123456789012:role/dd-conf-gen",
+	}, key)
+}
+
+func TestClientCache_Get(t *testing.T) {
+	t.Run("clients are reused for the same key", func(t *testing.T) {
+		var loaded []ClientKey
+		cache := newTestCache(&loaded)
+		ctx := context.Background()
+
+		first, err := cache.Get(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		second, err := cache.Get(ctx, providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}},
+		})
+		require.NoError(t, err)
+
+		assert.Same(t, first, second)
+		assert.Len(t, loaded, 1)
+	})
+
+	t.Run("clients are separated by region, profile and role", func(t *testing.T) {
+		var loaded []ClientKey
+		cache := newTestCache(&loaded)
+		ctx := context.Background()
+
+		tokyo, err := cache.Get(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		virginia, err := cache.Get(ctx, providers.ProviderConfig{Region: "us-east-1"})
+		require.NoError(t, err)
+		profile, err := cache.Get(ctx, providers.ProviderConfig{Region: "us-east-1", Profile: "staging"})
+		require.NoError(t, err)
+		role, err := cache.Get(ctx, providers.ProviderConfig{Region: "us-east-1", RoleARN: "arn:aws:iam::123456789012:role/reader"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "ap-northeast-1", tokyo.region)
+		assert.Equal(t, "us-east-1", virginia.region)
+		assert.NotSame(t, virginia, profile)
+		assert.NotSame(t, virginia, role)
+		assert.NotSame(t, profile, role)
+		assert.Len(t, loaded, 4)
+	})
+
+	t.Run("different keys load in parallel", func(t *testing.T) {
+		cache := NewClientCache(func(awsCfg aws.Config) *testClients {
+			return &testClients{region: awsCfg.Region}
+		})
+		// Each load blocks until both keys are loading at the same time
+		var loading sync.WaitGroup
+		loading.Add(2)
+		cache.loadConfig = func(ctx context.Context, key ClientKey) (aws.Config, error) {
+			loading.Done()
+			loading.Wait()
+			return aws.Config{Region: key.Region}, nil
+		}
+
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		for _, region := range []string{"ap-northeast-1", "us-east-1"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := cache.Get(context.Background(), providers.ProviderConfig{Region: region})
+				assert.NoError(t, err)
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("loads of different keys were serialized")
+		}
+	})
+
+	t.Run("same key loads once under concurrency", func(t *testing.T) {
+		cache := NewClientCache(func(awsCfg aws.Config) *testClients {
+			return &testClients{region: awsCfg.Region}
+		})
+		var calls atomic.Int32
+		cache.loadConfig = func(ctx context.Context, key ClientKey) (aws.Config, error) {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			return aws.Config{Region: key.Region}, nil
+		}
+
+		results := make([]*testClients, 8)
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				clients, err := cache.Get(context.Background(), providers.ProviderConfig{Region: "us-east-1"})
+				assert.NoError(t, err)
+				results[i] = clients
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+		for _, clients := range results {
+			assert.Same(t, results[0], clients)
+		}
+	})
+
+	t.Run("load error is not cached", func(t *testing.T) {
+		cache := NewClientCache(func(awsCfg aws.Config) *testClients {
+			return &testClients{region: awsCfg.Region}
+		})
+		calls := 0
+		cache.loadConfig = func(ctx context.Context, key ClientKey) (aws.Config, error) {
+			calls++
+			if calls == 1 {
+				return aws.Config{}, assert.AnError
+			}
+			return aws.Config{Region: key.Region}, nil
+		}
+
+		_, err := cache.Get(context.Background(), providers.ProviderConfig{Region: "us-east-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+
+		clients, err := cache.Get(context.Background(), providers.ProviderConfig{Region: "us-east-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "us-east-1", clients.region)
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("missing profile", func(t *testing.T) {
+		t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+
+		_, err := LoadConfig(context.Background(), ClientKey{Region: "us-east-1", Profile: "nonexistent"})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load AWS config")
+	})
+
+	t.Run("region is applied", func(t *testing.T) {
+		awsCfg, err := LoadConfig(context.Background(), ClientKey{Region: "eu-west-1"})
+		require.NoError(t, err)
+		assert.Equal(t, "eu-west-1", awsCfg.Region)
+	})
+}
@@ -0,0 +1,160 @@
+package awsutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+)
+
+// ResourceGroupsTaggingAPI defines the Resource Groups Tagging API interface
+type ResourceGroupsTaggingAPI interface {
+	GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error)
+}
+
+// ValidateConfig checks the configuration common to providers that filter by tags
+func ValidateConfig(cfg providers.ProviderConfig) error {
+	if cfg.Region == "" {
+		return fmt.Errorf("region is required")
+	}
+
+	// Check if filters contains tags
+	if cfg.Filters != nil {
+		if _, ok := cfg.Filters["tags"]; ok {
+			// tags should be a map
+			if _, ok := cfg.Filters["tags"].(map[string]interface{}); !ok {
+				return fmt.Errorf("filters.tags must be a map")
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExtractTagFilters extracts tag filters from the filters map
+func ExtractTagFilters(filters map[string]interface{}) map[string]string {
+	tags := make(map[string]string)
+	if filters == nil {
+		return tags
+	}
+
+	if tagsInterface, ok := filters["tags"]; ok {
+		if tagsMap, ok := tagsInterface.(map[string]interface{}); ok {
+			for k, v := range tagsMap {
+				if strVal, ok := v.(string); ok {
+					tags[k] = strVal
+				}
+			}
+		}
+	}
+
+	return tags
+}
+
+// GetResourcesByTags retrieves all resources of a type that match every tag, following pagination
+func GetResourcesByTags(ctx context.Context, taggingClient ResourceGroupsTaggingAPI, resourceType string, tags map[string]string) ([]taggingtypes.ResourceTagMapping, error) {
+	tagFilters := BuildTagFilters(tags)
+	slog.Debug("Calling GetResources API",
+		"resource_type", resourceType,
+		"tag_filters_count", len(tagFilters))
+
+	var mappings []taggingtypes.ResourceTagMapping
+	var paginationToken *string
+	for {
+		input := &resourcegroupstaggingapi.GetResourcesInput{
+			ResourceTypeFilters: []string{resourceType},
+			TagFilters:          tagFilters,
+			PaginationToken:     paginationToken,
+		}
+
+		// Catch panic and convert to error
+		var output *resourcegroupstaggingapi.GetResourcesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during GetResources API call: %v", r)
+				}
+			}()
+			output, err = taggingClient.GetResources(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resources by tags: %w", err)
+		}
+
+		mappings = append(mappings, output.ResourceTagMappingList...)
+
+		// An empty token means the last page has been read
+		if aws.ToString(output.PaginationToken) == "" {
+			break
+		}
+		paginationToken = output.PaginationToken
+	}
+
+	slog.Debug("GetResources API call succeeded", "resource_type", resourceType, "resources_count", len(mappings))
+
+	// Log the ARNs of found resources
+	if len(mappings) > 0 {
+		arns := make([]string, 0, len(mappings))
+		for _, mapping := range mappings {
+			if mapping.ResourceARN != nil {
+				arns = append(arns, *mapping.ResourceARN)
+			}
+		}
+		slog.Debug("Found resource ARNs", "arns", arns)
+	}
+
+	return mappings, nil
+}
+
+// BuildTagFilters converts a map of tags to AWS TagFilter array
+func BuildTagFilters(tags map[string]string) []taggingtypes.TagFilter {
+	tagFilters := []taggingtypes.TagFilter{}
+	for key, value := range tags {
+		tagFilters = append(tagFilters, taggingtypes.TagFilter{
+			Key:    aws.String(key),
+			Values: []string{value},
+		})
+	}
+	return tagFilters
+}
+
+// BuildARNToTagsMap builds a map from ARN to tags
+func BuildARNToTagsMap(resourceTagMappings []taggingtypes.ResourceTagMapping) map[string]map[string]string {
+	arnToTags := make(map[string]map[string]string)
+	for _, mapping := range resourceTagMappings {
+		arn := aws.ToString(mapping.ResourceARN)
+		tagsMap := make(map[string]string)
+		for _, tag := range mapping.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tagsMap[*tag.Key] = *tag.Value
+			}
+		}
+		arnToTags[arn] = tagsMap
+	}
+	return arnToTags
+}
+
+// ResourceIDFromARN returns the last colon- or slash-separated part of an ARN,
+// which is the resource ID for most services
+func ResourceIDFromARN(arn string) string {
+	return arn[strings.LastIndexAny(arn, ":/")+1:]
+}
+
+// MatchTags reports whether the resource has every tag in filter with the same value.
+// It is used for services whose tags are not searchable through the Resource Groups
+// Tagging API and mirrors the AND semantics of GetResourcesByTags.
+func MatchTags(resourceTags, filter map[string]string) bool {
+	for key, value := range filter {
+		if v, ok := resourceTags[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package awsutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockResourceGroupsTaggingClient is a mock implementation of ResourceGroupsTaggingAPI
+type MockResourceGroupsTaggingClient struct {
+	mock.Mock
+}
+
+func (m *MockResourceGroupsTaggingClient) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*resourcegroupstaggingapi.GetResourcesOutput), args.Error(1)
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		assert.NoError(t, ValidateConfig(providers.ProviderConfig{Region: "us-east-1"}))
+	})
+
+	t.Run("missing region", func(t *testing.T) {
+		err := ValidateConfig(providers.ProviderConfig{})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "region is required")
+	})
+
+	t.Run("invalid tags filter type", func(t *testing.T) {
+		err := ValidateConfig(providers.ProviderConfig{
+			Region:  "us-east-1",
+			Filters: map[string]interface{}{"tags": "invalid"},
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "filters.tags must be a map")
+	})
+}
+
+func TestGetResourcesByTags(t *testing.T) {
+	t.Run("pages are followed", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		ctx := context.Background()
+
+		mockTagging.On("GetResources", ctx, mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+			return input.PaginationToken == nil &&
+				assert.ObjectsAreEqual([]string{"rds:db"}, input.ResourceTypeFilters) &&
+				len(input.TagFilters) == 1
+		}), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{{ResourceARN: aws.String("arn:aws:rds:us-east-1:123456789012:db:first")}},
+			PaginationToken:        aws.String("next"),
+		}, nil).Once()
+		mockTagging.On("GetResources", ctx, mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+			return aws.ToString(input.PaginationToken) == "next"
+		}), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{{ResourceARN: aws.String("arn:aws:rds:us-east-1:123456789012:db:second")}},
+		}, nil).Once()
+
+		mappings, err := GetResourcesByTags(ctx, mockTagging, "rds:db", map[string]string{"env": "prod"})
+		require.NoError(t, err)
+		require.Len(t, mappings, 2)
+		assert.Equal(t, "arn:aws:rds:us-east-1:123456789012:db:first", *mappings[0].ResourceARN)
+		assert.Equal(t, "arn:aws:rds:us-east-1:123456789012:db:second", *mappings[1].ResourceARN)
+
+		mockTagging.AssertExpectations(t)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockTagging.On("GetResources", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := GetResourcesByTags(context.Background(), mockTagging, "rds:db", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get resources by tags")
+	})
+}
+
+func TestExtractTagFilters(t *testing.T) {
+	t.Run("extract tags from filters", func(t *testing.T) {
+		filters := map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Environment": "production",
+				"Team":        "backend",
+			},
+		}
+
+		result := ExtractTagFilters(filters)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "production", result["Environment"])
+		assert.Equal(t, "backend", result["Team"])
+	})
+
+	t.Run("no tags in filters", func(t *testing.T) {
+		filters := map[string]interface{}{
+			"other": "value",
+		}
+
+		result := ExtractTagFilters(filters)
+		assert.Len(t, result, 0)
+	})
+
+	t.Run("nil filters", func(t *testing.T) {
+		result := ExtractTagFilters(nil)
+		assert.Len(t, result, 0)
+	})
+
+	t.Run("non-string tag values", func(t *testing.T) {
+		filters := map[string]interface{}{
+			"tags": map[string]interface{}{
+				"String": "value",
+				"Number": 123,
+				"Bool":   true,
+			},
+		}
+
+		result := ExtractTagFilters(filters)
+		assert.Len(t, result, 1)
+		assert.Equal(t, "value", result["String"])
+	})
+}
+
+func TestBuildTagFilters(t *testing.T) {
+	t.Run("build tag filters", func(t *testing.T) {
+		tags := map[string]string{
+			"Environment": "production",
+			"Team":        "backend",
+		}
+
+		tagFilters := BuildTagFilters(tags)
+		assert.Len(t, tagFilters, 2)
+
+		tagMap := make(map[string]string)
+		for _, filter := range tagFilters {
+			require.NotNil(t, filter.Key)
+			require.Len(t, filter.Values, 1)
+			tagMap[*filter.Key] = filter.Values[0]
+		}
+
+		assert.Equal(t, "production", tagMap["Environment"])
+		assert.Equal(t, "backend", tagMap["Team"])
+	})
+
+	t.Run("empty tags", func(t *testing.T) {
+		tags := map[string]string{}
+		tagFilters := BuildTagFilters(tags)
+		assert.Len(t, tagFilters, 0)
+	})
+}
+
+func TestBuildARNToTagsMap(t *testing.T) {
+	arnToTags := BuildARNToTagsMap([]taggingtypes.ResourceTagMapping{
+		{
+			ResourceARN: aws.String("arn:aws:rds:us-east-1:123456789012:db:first"),
+			Tags: []taggingtypes.Tag{
+				{Key: aws.String("env"), Value: aws.String("prod")},
+				{Key: aws.String("empty"), Value: nil},
+			},
+		},
+	})
+
+	assert.Equal(t, map[string]map[string]string{
+		"arn:aws:rds:us-east-1:123456789012:db:first": {"env": "prod"},
+	}, arnToTags)
+}
+
+func TestResourceIDFromARN(t *testing.T) {
+	assert.Equal(t, "my-cluster", ResourceIDFromARN("arn:aws:elasticache:ap-northeast-1:123456789012:cluster:my-cluster"))
+	assert.Equal(t, "my-cluster", ResourceIDFromARN("arn:aws:memorydb:us-east-1:123456789012:cluster/my-cluster"))
+	assert.Equal(t, "plain-id", ResourceIDFromARN("plain-id"))
+}
+
+func TestMatchTags(t *testing.T) {
+	resourceTags := map[string]string{"Environment": "production", "Team": "backend"}
+
+	assert.True(t, MatchTags(resourceTags, nil))
+	assert.True(t, MatchTags(resourceTags, map[string]string{"Environment": "production"}))
+	assert.True(t, MatchTags(resourceTags, map[string]string{"Environment": "production", "Team": "backend"}))
+	assert.False(t, MatchTags(resourceTags, map[string]string{"Environment": "staging"}))
+	assert.False(t, MatchTags(resourceTags, map[string]string{"Environment": "production", "Service": "api"}))
+	assert.False(t, MatchTags(nil, map[string]string{"Environment": "production"}))
+}
@@ -0,0 +1,238 @@
+package cloudmap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicediscovery"
+	sdtypes "github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "cloudmap_instances"
+
+const (
+	// namespaceFilter is the HTTP name of the namespace to discover instances in
+	namespaceFilter = "namespace"
+	// serviceFilter is the name of the service to discover instances of
+	serviceFilter = "service"
+	// attributesFilter restricts discovery to instances with all the given custom attributes
+	attributesFilter = "attributes"
+	// healthStatusFilter restricts discovery to instances with the given health status
+	healthStatusFilter = "health_status"
+	// portFilter is the port used for instances without the AWS_INSTANCE_PORT attribute
+	portFilter = "port"
+)
+
+const (
+	// ipv4Attribute is the attribute holding the IPv4 address of an instance
+	ipv4Attribute = "AWS_INSTANCE_IPV4"
+	// portAttribute is the attribute holding the port of an instance
+	portAttribute = "AWS_INSTANCE_PORT"
+)
+
+// defaultHealthStatus returns every instance regardless of its health status
+const defaultHealthStatus = sdtypes.HealthStatusFilterAll
+
+// discoverMaxResults is the maximum number of instances returned by DiscoverInstances
+const discoverMaxResults = 1000
+
+// Provider implements the providers.Provider interface for Cloud Map service instances
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	ServiceDiscovery ServiceDiscoveryAPI
+}
+
+// ServiceDiscoveryAPI defines the Cloud Map API interface
+type ServiceDiscoveryAPI interface {
+	DiscoverInstances(ctx context.Context, params *servicediscovery.DiscoverInstancesInput, optFns ...func(*servicediscovery.Options)) (*servicediscovery.DiscoverInstancesOutput, error)
+}
+
+// NewProvider creates a new Cloud Map provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new Cloud Map provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		ServiceDiscovery: servicediscovery.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	// Cloud Map instances have no tags; attributes are used instead
+	if _, ok := cfg.Filters["tags"]; ok {
+		return fmt.Errorf("filters.tags is not supported, use filters.%s instead", attributesFilter)
+	}
+
+	for _, key := range []string{namespaceFilter, serviceFilter} {
+		value, err := providers.StringFilter(cfg.Filters, key)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			return fmt.Errorf("filters.%s is required", key)
+		}
+	}
+
+	if _, err := providers.StringMapFilter(cfg.Filters, attributesFilter); err != nil {
+		return err
+	}
+
+	healthStatus, err := providers.StringFilter(cfg.Filters, healthStatusFilter)
+	if err != nil {
+		return err
+	}
+	if healthStatus != "" && !isValidHealthStatus(healthStatus) {
+		return fmt.Errorf("filters.%s contains unknown health status %q", healthStatusFilter, healthStatus)
+	}
+
+	port, err := providers.IntFilter(cfg.Filters, portFilter)
+	if err != nil {
+		return err
+	}
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("filters.%s must be between 0 and 65535", portFilter)
+	}
+	return nil
+}
+
+// Discover retrieves the instances of a Cloud Map service based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting Cloud Map discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	namespace, _ := providers.StringFilter(cfg.Filters, namespaceFilter)
+	service, _ := providers.StringFilter(cfg.Filters, serviceFilter)
+	attributes, _ := providers.StringMapFilter(cfg.Filters, attributesFilter)
+	port, _ := providers.IntFilter(cfg.Filters, portFilter)
+	healthStatus, _ := providers.StringFilter(cfg.Filters, healthStatusFilter)
+	if healthStatus == "" {
+		healthStatus = string(defaultHealthStatus)
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Extracted attribute filters", "attribute_count", len(attributes), "attributes", attributes,
+		"namespace", namespace, "service", service, "health_status", healthStatus)
+
+	input := &servicediscovery.DiscoverInstancesInput{
+		NamespaceName:   aws.String(namespace),
+		ServiceName:     aws.String(service),
+		HealthStatus:    sdtypes.HealthStatusFilter(healthStatus),
+		MaxResults:      aws.Int32(discoverMaxResults),
+		QueryParameters: attributes,
+	}
+
+	// Catch panic and convert to error
+	var resp *servicediscovery.DiscoverInstancesOutput
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during DiscoverInstances API call: %v", r)
+			}
+		}()
+		resp, err = clients.ServiceDiscovery.DiscoverInstances(ctx, input)
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover instances of Cloud Map service %s.%s: %w", service, namespace, err)
+	}
+
+	if len(resp.Instances) == 0 {
+		slog.Info("No Cloud Map instances found matching filters", "namespace", namespace, "service", service, "attributes", attributes)
+		return []providers.Resource{}, nil
+	}
+
+	instances := resp.Instances
+	sort.SliceStable(instances, func(i, j int) bool {
+		return aws.ToString(instances[i].InstanceId) < aws.ToString(instances[j].InstanceId)
+	})
+
+	var result []providers.Resource
+	for _, instance := range instances {
+		instanceID := aws.ToString(instance.InstanceId)
+
+		host := instance.Attributes[ipv4Attribute]
+		if host == "" {
+			slog.Warn("Cloud Map instance has no IPv4 address", "instance_id", instanceID, "service", service)
+			continue
+		}
+
+		instancePort := port
+		if value, ok := instance.Attributes[portAttribute]; ok {
+			instancePort, err = strconv.Atoi(value)
+			if err != nil || instancePort < 0 || instancePort > 65535 {
+				slog.Warn("Cloud Map instance has an invalid port attribute",
+					"instance_id", instanceID,
+					"service", service,
+					"value", value)
+				continue
+			}
+		}
+
+		slog.Debug("Extracted instance",
+			"host", host,
+			"port", instancePort,
+			"instance_id", instanceID)
+
+		result = append(result, providers.Resource{
+			Host: host,
+			Port: instancePort,
+			Tags: map[string]string{},
+			Metadata: map[string]interface{}{
+				"InstanceID":    instanceID,
+				"NamespaceName": aws.ToString(instance.NamespaceName),
+				"ServiceName":   aws.ToString(instance.ServiceName),
+				"HealthStatus":  string(instance.HealthStatus),
+				"Attributes":    instance.Attributes,
+			},
+		})
+	}
+
+	slog.Info("Cloud Map discovery completed", "total_instances", len(result))
+	return result, nil
+}
+
+// isValidHealthStatus reports whether status is a known DiscoverInstances health status filter
+func isValidHealthStatus(status string) bool {
+	for _, valid := range sdtypes.HealthStatusFilter("").Values() {
+		if string(valid) == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package cloudmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/servicediscovery"
+	sdtypes "github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockServiceDiscoveryClient is a mock implementation of ServiceDiscoveryAPI
+type MockServiceDiscoveryClient struct {
+	mock.Mock
+}
+
+func (m *MockServiceDiscoveryClient) DiscoverInstances(ctx context.Context, params *servicediscovery.DiscoverInstancesInput, optFns ...func(*servicediscovery.Options)) (*servicediscovery.DiscoverInstancesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*servicediscovery.DiscoverInstancesOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client ServiceDiscoveryAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{ServiceDiscovery: client}, nil
+	})
+}
+
+// newInstance returns a healthy instance of the payments service with the given attributes
+func newInstance(id string, kv ...string) sdtypes.HttpInstanceSummary {
+	instance := sdtypes.HttpInstanceSummary{
+		InstanceId:    aws.String(id),
+		NamespaceName: aws.String("internal.example.com"),
+		ServiceName:   aws.String("payments"),
+		HealthStatus:  sdtypes.HealthStatusHealthy,
+		Attributes:    map[string]string{},
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		instance.Attributes[kv[i]] = kv[i+1]
+	}
+	return instance
+}
+
+// newConfig returns a configuration for the payments service with the given extra filters
+func newConfig(filters map[string]interface{}) providers.ProviderConfig {
+	cfg := providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"namespace": "internal.example.com",
+			"service":   "payments",
+		},
+	}
+	for k, v := range filters {
+		cfg.Filters[k] = v
+	}
+	return cfg
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "cloudmap_instances", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(newConfig(map[string]interface{}{
+		"attributes":    map[string]interface{}{"stage": "prod"},
+		"health_status": "HEALTHY",
+		"port":          8080,
+	})))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"})
+	assert.EqualError(t, err, "filters.namespace is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"namespace": "internal.example.com"},
+	})
+	assert.EqualError(t, err, "filters.service is required")
+
+	err = provider.ValidateConfig(newConfig(map[string]interface{}{"tags": map[string]interface{}{"Environment": "production"}}))
+	assert.EqualError(t, err, "filters.tags is not supported, use filters.attributes instead")
+
+	err = provider.ValidateConfig(newConfig(map[string]interface{}{"attributes": "stage=prod"}))
+	assert.EqualError(t, err, "filters.attributes must be a map of strings")
+
+	err = provider.ValidateConfig(newConfig(map[string]interface{}{"health_status": "healthy"}))
+	assert.EqualError(t, err, `filters.health_status contains unknown health status "healthy"`)
+
+	err = provider.ValidateConfig(newConfig(map[string]interface{}{"port": 70000}))
+	assert.EqualError(t, err, "filters.port must be between 0 and 65535")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockSD := new(MockServiceDiscoveryClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockSD)
+
+		mockSD.On("DiscoverInstances", mock.Anything, &servicediscovery.DiscoverInstancesInput{
+			NamespaceName:   aws.String("internal.example.com"),
+			ServiceName:     aws.String("payments"),
+			HealthStatus:    sdtypes.HealthStatusFilterAll,
+			MaxResults:      aws.Int32(1000),
+			QueryParameters: map[string]string{"stage": "prod"},
+		}, mock.Anything).Return(&servicediscovery.DiscoverInstancesOutput{
+			Instances: []sdtypes.HttpInstanceSummary{
+				newInstance("i-bbb", "AWS_INSTANCE_IPV4", "10.0.2.20", "AWS_INSTANCE_PORT", "8080", "stage", "prod"),
+				newInstance("i-aaa", "AWS_INSTANCE_IPV4", "10.0.1.10", "AWS_INSTANCE_PORT", "8080", "stage", "prod", "version", "v2"),
+				newInstance("i-ccc", "AWS_INSTANCE_IPV4", "10.0.3.30", "stage", "prod"),
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, newConfig(map[string]interface{}{
+			"attributes": map[string]interface{}{"stage": "prod"},
+			"port":       9000,
+		}))
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		resource := result[0]
+		assert.Equal(t, "10.0.1.10", resource.Host)
+		assert.Equal(t, 8080, resource.Port)
+		assert.Empty(t, resource.Tags)
+		assert.Equal(t, "i-aaa", resource.Metadata["InstanceID"])
+		assert.Equal(t, "internal.example.com", resource.Metadata["NamespaceName"])
+		assert.Equal(t, "payments", resource.Metadata["ServiceName"])
+		assert.Equal(t, "HEALTHY", resource.Metadata["HealthStatus"])
+		assert.Equal(t, map[string]string{
+			"AWS_INSTANCE_IPV4": "10.0.1.10",
+			"AWS_INSTANCE_PORT": "8080",
+			"stage":             "prod",
+			"version":           "v2",
+		}, resource.Metadata["Attributes"])
+
+		assert.Equal(t, "i-bbb", result[1].Metadata["InstanceID"])
+		assert.Equal(t, "10.0.3.30", result[2].Host)
+		assert.Equal(t, 9000, result[2].Port)
+
+		mockSD.AssertExpectations(t)
+	})
+
+	t.Run("health status filter", func(t *testing.T) {
+		mockSD := new(MockServiceDiscoveryClient)
+		provider := newTestProvider(mockSD)
+
+		mockSD.On("DiscoverInstances", mock.Anything, mock.MatchedBy(func(input *servicediscovery.DiscoverInstancesInput) bool {
+			return input.HealthStatus == sdtypes.HealthStatusFilterHealthyOrElseAll && input.QueryParameters == nil
+		}), mock.Anything).Return(&servicediscovery.DiscoverInstancesOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), newConfig(map[string]interface{}{
+			"health_status": "HEALTHY_OR_ELSE_ALL",
+		}))
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockSD.AssertExpectations(t)
+	})
+
+	t.Run("instances without address or with invalid port are skipped", func(t *testing.T) {
+		mockSD := new(MockServiceDiscoveryClient)
+		provider := newTestProvider(mockSD)
+
+		mockSD.On("DiscoverInstances", mock.Anything, mock.Anything, mock.Anything).Return(&servicediscovery.DiscoverInstancesOutput{
+			Instances: []sdtypes.HttpInstanceSummary{
+				newInstance("i-aaa", "AWS_INSTANCE_CNAME", "payments.example.com"),
+				newInstance("i-bbb", "AWS_INSTANCE_IPV4", "10.0.2.20", "AWS_INSTANCE_PORT", "http"),
+				newInstance("i-ccc", "AWS_INSTANCE_IPV4", "10.0.3.30", "AWS_INSTANCE_PORT", "8080"),
+			},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), newConfig(nil))
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "i-ccc", result[0].Metadata["InstanceID"])
+	})
+
+	t.Run("discover error", func(t *testing.T) {
+		mockSD := new(MockServiceDiscoveryClient)
+		provider := newTestProvider(mockSD)
+
+		mockSD.On("DiscoverInstances", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), newConfig(nil))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to discover instances of Cloud Map service payments.internal.example.com")
+	})
+}
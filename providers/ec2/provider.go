@@ -0,0 +1,328 @@
+package ec2
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "ec2_instance"
+
+const (
+	// statesFilter restricts discovery to instances in the listed states
+	statesFilter = "states"
+	// addressTypeFilter selects the address used as Host
+	addressTypeFilter = "address_type"
+	// portFilter is the port of every instance
+	portFilter = "port"
+	// portTagFilter is the key of the instance tag holding the port, which takes
+	// precedence over filters.port
+	portTagFilter = "port_tag"
+)
+
+// defaultStates are the instance states discovered when filters.states is not set
+var defaultStates = []string{string(ec2types.InstanceStateNameRunning)}
+
+// Address types accepted in filters.address_type
+const (
+	addressTypePrivateIP  = "private_ip"
+	addressTypePrivateDNS = "private_dns"
+	addressTypePublicIP   = "public_ip"
+	addressTypePublicDNS  = "public_dns"
+)
+
+// defaultAddressType is used when filters.address_type is not set
+const defaultAddressType = addressTypePrivateIP
+
+// describePageSize is the MaxResults value used when describing instances
+const describePageSize = 1000
+
+// Provider implements the providers.Provider interface for EC2 instances
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	EC2 EC2API
+}
+
+// EC2API defines the EC2 API interface
+type EC2API interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+}
+
+// NewProvider creates a new EC2 provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new EC2 provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		EC2: ec2.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	states, err := providers.StringListFilter(cfg.Filters, statesFilter)
+	if err != nil {
+		return err
+	}
+	validStates := ec2types.InstanceStateName("").Values()
+	for _, state := range states {
+		if !isValidState(state, validStates) {
+			return fmt.Errorf("filters.%s contains unknown instance state %q", statesFilter, state)
+		}
+	}
+
+	addressType, err := providers.StringFilter(cfg.Filters, addressTypeFilter)
+	if err != nil {
+		return err
+	}
+	switch addressType {
+	case "", addressTypePrivateIP, addressTypePrivateDNS, addressTypePublicIP, addressTypePublicDNS:
+	default:
+		return fmt.Errorf("filters.%s must be one of %s, %s, %s or %s", addressTypeFilter,
+			addressTypePrivateIP, addressTypePrivateDNS, addressTypePublicIP, addressTypePublicDNS)
+	}
+
+	port, err := providers.IntFilter(cfg.Filters, portFilter)
+	if err != nil {
+		return err
+	}
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("filters.%s must be between 0 and 65535", portFilter)
+	}
+
+	_, err = providers.StringFilter(cfg.Filters, portTagFilter)
+	return err
+}
+
+// Discover retrieves EC2 instances based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting EC2 discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	states, _ := providers.StringListFilter(cfg.Filters, statesFilter)
+	if len(states) == 0 {
+		states = defaultStates
+	}
+	addressType, _ := providers.StringFilter(cfg.Filters, addressTypeFilter)
+	if addressType == "" {
+		addressType = defaultAddressType
+	}
+	port, _ := providers.IntFilter(cfg.Filters, portFilter)
+	portTag, _ := providers.StringFilter(cfg.Filters, portTagFilter)
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags, "states", states, "address_type", addressType)
+
+	// Tags and states are filtered by the EC2 API itself
+	instances, err := describeInstances(ctx, clients.EC2, instanceFilters(tags, states))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(instances) == 0 {
+		slog.Info("No EC2 instances found matching filters", "tags", tags, "states", states)
+		return []providers.Resource{}, nil
+	}
+
+	var result []providers.Resource
+	for _, instance := range instances {
+		instanceID := aws.ToString(instance.InstanceId)
+
+		host := instanceAddress(instance, addressType)
+		if host == "" {
+			slog.Warn("EC2 instance has no address", "instance_id", instanceID, "address_type", addressType)
+			continue
+		}
+
+		instanceTags := tagsToMap(instance.Tags)
+		instancePort := port
+		if portTag != "" {
+			if value, ok := instanceTags[portTag]; ok {
+				instancePort, err = strconv.Atoi(value)
+				if err != nil || instancePort < 0 || instancePort > 65535 {
+					slog.Warn("EC2 instance has an invalid port tag",
+						"instance_id", instanceID,
+						"tag", portTag,
+						"value", value)
+					continue
+				}
+			}
+		}
+
+		resource := newInstanceResource(instance, host, instancePort, instanceTags)
+		slog.Debug("Extracted instance",
+			"host", resource.Host,
+			"port", resource.Port,
+			"instance_id", instanceID)
+		result = append(result, resource)
+	}
+
+	slog.Info("EC2 discovery completed", "total_instances", len(result))
+	return result, nil
+}
+
+// isValidState reports whether state is one of the valid instance states
+func isValidState(state string, validStates []ec2types.InstanceStateName) bool {
+	for _, valid := range validStates {
+		if state == string(valid) {
+			return true
+		}
+	}
+	return false
+}
+
+// instanceFilters returns the DescribeInstances filters for the tags and states.
+// Filters are ordered by tag key so that requests are deterministic.
+func instanceFilters(tags map[string]string, states []string) []ec2types.Filter {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]ec2types.Filter, 0, len(keys)+1)
+	for _, key := range keys {
+		filters = append(filters, ec2types.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []string{tags[key]},
+		})
+	}
+	filters = append(filters, ec2types.Filter{
+		Name:   aws.String("instance-state-name"),
+		Values: states,
+	})
+	return filters
+}
+
+// describeInstances lists all instances matching the filters, following NextToken
+func describeInstances(ctx context.Context, client EC2API, filters []ec2types.Filter) ([]ec2types.Instance, error) {
+	var result []ec2types.Instance
+	var nextToken *string
+
+	for {
+		input := &ec2.DescribeInstancesInput{
+			Filters:    filters,
+			MaxResults: aws.Int32(describePageSize),
+			NextToken:  nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *ec2.DescribeInstancesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeInstances API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeInstances(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EC2 instances: %w", err)
+		}
+		for _, reservation := range resp.Reservations {
+			result = append(result, reservation.Instances...)
+		}
+
+		if aws.ToString(resp.NextToken) == "" {
+			return result, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// instanceAddress returns the address of the instance for the address type
+func instanceAddress(instance ec2types.Instance, addressType string) string {
+	switch addressType {
+	case addressTypePrivateDNS:
+		return aws.ToString(instance.PrivateDnsName)
+	case addressTypePublicIP:
+		return aws.ToString(instance.PublicIpAddress)
+	case addressTypePublicDNS:
+		return aws.ToString(instance.PublicDnsName)
+	default:
+		return aws.ToString(instance.PrivateIpAddress)
+	}
+}
+
+// tagsToMap converts EC2 tags to a map
+func tagsToMap(tags []ec2types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[*tag.Key] = *tag.Value
+		}
+	}
+	return result
+}
+
+// newInstanceResource returns the resource for an instance
+func newInstanceResource(instance ec2types.Instance, host string, port int, tags map[string]string) providers.Resource {
+	var availabilityZone string
+	if instance.Placement != nil {
+		availabilityZone = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	var state string
+	if instance.State != nil {
+		state = string(instance.State.Name)
+	}
+
+	return providers.Resource{
+		Host: host,
+		Port: port,
+		Tags: tags,
+		Metadata: map[string]interface{}{
+			"InstanceID":       aws.ToString(instance.InstanceId),
+			"InstanceType":     string(instance.InstanceType),
+			"AvailabilityZone": availabilityZone,
+			"VpcID":            aws.ToString(instance.VpcId),
+			"SubnetID":         aws.ToString(instance.SubnetId),
+			"PrivateIPAddress": aws.ToString(instance.PrivateIpAddress),
+			"PrivateDNSName":   aws.ToString(instance.PrivateDnsName),
+			"PublicIPAddress":  aws.ToString(instance.PublicIpAddress),
+			"PublicDNSName":    aws.ToString(instance.PublicDnsName),
+			"State":            state,
+			"ImageID":          aws.ToString(instance.ImageId),
+		},
+	}
+}
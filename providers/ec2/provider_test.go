@@ -0,0 +1,269 @@
+package ec2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockEC2Client is a mock implementation of EC2API
+type MockEC2Client struct {
+	mock.Mock
+}
+
+func (m *MockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ec2.DescribeInstancesOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client EC2API) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{EC2: client}, nil
+	})
+}
+
+// newInstance returns a running instance with private and public addresses and the given tags
+func newInstance(id, privateIP string, kv ...string) ec2types.Instance {
+	var tags []ec2types.Tag
+	for i := 0; i+1 < len(kv); i += 2 {
+		tags = append(tags, ec2types.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+
+	return ec2types.Instance{
+		InstanceId:       aws.String(id),
+		InstanceType:     ec2types.InstanceTypeM7gLarge,
+		ImageId:          aws.String("ami-0123456789abcdef0"),
+		Placement:        &ec2types.Placement{AvailabilityZone: aws.String("ap-northeast-1a")},
+		VpcId:            aws.String("vpc-0123"),
+		SubnetId:         aws.String("subnet-0123"),
+		PrivateIpAddress: aws.String(privateIP),
+		PrivateDnsName:   aws.String("ip-" + privateIP + ".ap-northeast-1.compute.internal"),
+		PublicIpAddress:  aws.String("203.0.113.10"),
+		PublicDnsName:    aws.String("ec2-203-0-113-10.ap-northeast-1.compute.amazonaws.com"),
+		State:            &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		Tags:             tags,
+	}
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "ec2_instance", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name    string
+		filters map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "valid filters",
+			filters: map[string]interface{}{
+				"tags":         map[string]interface{}{"Role": "web"},
+				"states":       []interface{}{"running", "stopped"},
+				"address_type": "private_dns",
+				"port":         8080,
+				"port_tag":     "Port",
+			},
+		},
+		{
+			name:    "unknown state",
+			filters: map[string]interface{}{"states": []interface{}{"up"}},
+			wantErr: `filters.states contains unknown instance state "up"`,
+		},
+		{
+			name:    "unknown address type",
+			filters: map[string]interface{}{"address_type": "ipv6"},
+			wantErr: "filters.address_type must be one of private_ip, private_dns, public_ip or public_dns",
+		},
+		{
+			name:    "port is not an integer",
+			filters: map[string]interface{}{"port": "8080"},
+			wantErr: "filters.port must be an integer",
+		},
+		{
+			name:    "port out of range",
+			filters: map[string]interface{}{"port": 70000},
+			wantErr: "filters.port must be between 0 and 65535",
+		},
+		{
+			name:    "port tag is not a string",
+			filters: map[string]interface{}{"port_tag": 1},
+			wantErr: "filters.port_tag must be a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1", Filters: tt.filters})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockEC2 := new(MockEC2Client)
+		provider := newTestProvider(mockEC2)
+
+		filters := []ec2types.Filter{
+			{Name: aws.String("tag:Role"), Values: []string{"web"}},
+			{Name: aws.String("tag:env"), Values: []string{"prod"}},
+			{Name: aws.String("instance-state-name"), Values: []string{"running"}},
+		}
+		mockEC2.On("DescribeInstances", mock.Anything, &ec2.DescribeInstancesInput{
+			Filters:    filters,
+			MaxResults: aws.Int32(1000),
+		}, mock.Anything).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []ec2types.Reservation{
+				{Instances: []ec2types.Instance{newInstance("i-0001", "10.0.0.1", "Role", "web", "env", "prod")}},
+			},
+			NextToken: aws.String("next"),
+		}, nil).Once()
+		mockEC2.On("DescribeInstances", mock.Anything, &ec2.DescribeInstancesInput{
+			Filters:    filters,
+			MaxResults: aws.Int32(1000),
+			NextToken:  aws.String("next"),
+		}, mock.Anything).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []ec2types.Reservation{
+				{Instances: []ec2types.Instance{newInstance("i-0002", "10.0.0.2", "Role", "web", "env", "prod")}},
+			},
+		}, nil).Once()
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"env": "prod", "Role": "web"},
+				"port": 8080,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "10.0.0.1", resource.Host)
+		assert.Equal(t, 8080, resource.Port)
+		assert.Equal(t, "web", resource.Tags["Role"])
+		assert.Equal(t, "i-0001", resource.Metadata["InstanceID"])
+		assert.Equal(t, "m7g.large", resource.Metadata["InstanceType"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "vpc-0123", resource.Metadata["VpcID"])
+		assert.Equal(t, "subnet-0123", resource.Metadata["SubnetID"])
+		assert.Equal(t, "10.0.0.1", resource.Metadata["PrivateIPAddress"])
+		assert.Equal(t, "ip-10.0.0.1.ap-northeast-1.compute.internal", resource.Metadata["PrivateDNSName"])
+		assert.Equal(t, "203.0.113.10", resource.Metadata["PublicIPAddress"])
+		assert.Equal(t, "ec2-203-0-113-10.ap-northeast-1.compute.amazonaws.com", resource.Metadata["PublicDNSName"])
+		assert.Equal(t, "running", resource.Metadata["State"])
+		assert.Equal(t, "ami-0123456789abcdef0", resource.Metadata["ImageID"])
+
+		assert.Equal(t, "10.0.0.2", result[1].Host)
+
+		mockEC2.AssertExpectations(t)
+	})
+
+	t.Run("states filter and address type", func(t *testing.T) {
+		mockEC2 := new(MockEC2Client)
+		provider := newTestProvider(mockEC2)
+
+		mockEC2.On("DescribeInstances", mock.Anything, &ec2.DescribeInstancesInput{
+			Filters: []ec2types.Filter{
+				{Name: aws.String("instance-state-name"), Values: []string{"running", "stopped"}},
+			},
+			MaxResults: aws.Int32(1000),
+		}, mock.Anything).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []ec2types.Reservation{
+				{Instances: []ec2types.Instance{newInstance("i-0001", "10.0.0.1")}},
+			},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"states":       []interface{}{"running", "stopped"},
+				"address_type": "private_dns",
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "ip-10.0.0.1.ap-northeast-1.compute.internal", result[0].Host)
+		assert.Equal(t, 0, result[0].Port)
+		mockEC2.AssertExpectations(t)
+	})
+
+	t.Run("port from tag", func(t *testing.T) {
+		mockEC2 := new(MockEC2Client)
+		provider := newTestProvider(mockEC2)
+
+		noPublicIP := newInstance("i-0004", "10.0.0.4", "Port", "9000")
+		noPublicIP.PublicIpAddress = nil
+
+		mockEC2.On("DescribeInstances", mock.Anything, mock.Anything, mock.Anything).Return(&ec2.DescribeInstancesOutput{
+			Reservations: []ec2types.Reservation{
+				{Instances: []ec2types.Instance{
+					newInstance("i-0001", "10.0.0.1", "Port", "9090"),
+					newInstance("i-0002", "10.0.0.2"),
+					newInstance("i-0003", "10.0.0.3", "Port", "http"),
+				}},
+				{Instances: []ec2types.Instance{noPublicIP}},
+			},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"port":         8080,
+				"port_tag":     "Port",
+				"address_type": "public_ip",
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		// The tag takes precedence over filters.port
+		assert.Equal(t, "i-0001", result[0].Metadata["InstanceID"])
+		assert.Equal(t, 9090, result[0].Port)
+		// filters.port is used when the tag is missing
+		assert.Equal(t, "i-0002", result[1].Metadata["InstanceID"])
+		assert.Equal(t, 8080, result[1].Port)
+		// i-0003 has an invalid port tag and i-0004 has no public IP address
+	})
+
+	t.Run("no instances", func(t *testing.T) {
+		mockEC2 := new(MockEC2Client)
+		provider := newTestProvider(mockEC2)
+
+		mockEC2.On("DescribeInstances", mock.Anything, mock.Anything, mock.Anything).Return(&ec2.DescribeInstancesOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockEC2 := new(MockEC2Client)
+		provider := newTestProvider(mockEC2)
+
+		mockEC2.On("DescribeInstances", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe EC2 instances")
+	})
+}
@@ -0,0 +1,530 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "ecs_task"
+
+const (
+	// clustersFilter restricts discovery to the listed clusters (names or ARNs)
+	clustersFilter = "clusters"
+	// containerNamesFilter restricts the emitted resources to the listed containers
+	containerNamesFilter = "container_names"
+	// healthStatusFilter restricts discovery to tasks with one of the listed health statuses
+	healthStatusFilter = "health_status"
+)
+
+const (
+	// eniAttachmentType is the attachment type of the task ENI in awsvpc network mode
+	eniAttachmentType = "ElasticNetworkInterface"
+	// privateIPv4AddressDetail is the attachment detail holding the ENI private IP address
+	privateIPv4AddressDetail = "privateIPv4Address"
+)
+
+// serviceStatusActive is the status of services that are not being deleted
+const serviceStatusActive = "ACTIVE"
+
+// taskStatusRunning is the last status of running tasks
+const taskStatusRunning = "RUNNING"
+
+const (
+	// describeConcurrency is the maximum number of list and describe calls in flight
+	describeConcurrency = 5
+	// listPageSize is the MaxResults value used when listing clusters, services and tasks
+	listPageSize = 100
+	// describeServicesBatchSize is the maximum number of services accepted by DescribeServices
+	describeServicesBatchSize = 10
+	// describeTasksBatchSize is the maximum number of tasks accepted by DescribeTasks
+	describeTasksBatchSize = 100
+)
+
+// Provider implements the providers.Provider interface for ECS tasks
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	ECS ECSAPI
+}
+
+// ECSAPI defines the ECS API interface
+type ECSAPI interface {
+	ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error)
+	ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error)
+}
+
+// serviceTasks holds a service and its running tasks
+type serviceTasks struct {
+	service ecstypes.Service
+	tasks   []ecstypes.Task
+}
+
+// NewProvider creates a new ECS provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new ECS provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		ECS: ecs.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	if _, err := providers.StringListFilter(cfg.Filters, clustersFilter); err != nil {
+		return err
+	}
+
+	if _, err := providers.StringListFilter(cfg.Filters, containerNamesFilter); err != nil {
+		return err
+	}
+
+	healthStatuses, err := providers.StringListFilter(cfg.Filters, healthStatusFilter)
+	if err != nil {
+		return err
+	}
+	for _, status := range healthStatuses {
+		if !slices.Contains(ecstypes.HealthStatus("").Values(), ecstypes.HealthStatus(status)) {
+			return fmt.Errorf("filters.%s contains unknown health status %q", healthStatusFilter, status)
+		}
+	}
+	return nil
+}
+
+// Discover retrieves the container ports of running ECS tasks based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting ECS discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	clusters, _ := providers.StringListFilter(cfg.Filters, clustersFilter)
+	containerNames, _ := providers.StringListFilter(cfg.Filters, containerNamesFilter)
+	healthStatuses, _ := providers.StringListFilter(cfg.Filters, healthStatusFilter)
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags,
+		"clusters", clusters, "container_names", containerNames, "health_status", healthStatuses)
+
+	if len(clusters) == 0 {
+		clusters, err = listClusters(ctx, clients.ECS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Get the services of each cluster; results keep the order of the clusters
+	servicesPerCluster, err := providers.ParallelMap(ctx, clusters, describeConcurrency, func(ctx context.Context, cluster string) ([]ecstypes.Service, error) {
+		return listClusterServices(ctx, clients.ECS, cluster)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []ecstypes.Service
+	for _, clusterServices := range servicesPerCluster {
+		for _, service := range clusterServices {
+			if aws.ToString(service.Status) != serviceStatusActive {
+				continue
+			}
+			if !awsutil.MatchTags(tagsToMap(service.Tags), tags) {
+				continue
+			}
+			services = append(services, service)
+		}
+	}
+
+	if len(services) == 0 {
+		slog.Info("No ECS services found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found ECS services by tags", "count", len(services))
+
+	// Get the running tasks of each service; results keep the order of the services
+	tasksPerService, err := providers.ParallelMap(ctx, services, describeConcurrency, func(ctx context.Context, service ecstypes.Service) (serviceTasks, error) {
+		tasks, err := listServiceTasks(ctx, clients.ECS, aws.ToString(service.ClusterArn), aws.ToString(service.ServiceName))
+		if err != nil {
+			return serviceTasks{}, err
+		}
+		if len(healthStatuses) > 0 {
+			tasks = slices.DeleteFunc(tasks, func(task ecstypes.Task) bool {
+				return !slices.Contains(healthStatuses, string(task.HealthStatus))
+			})
+		}
+		return serviceTasks{service: service, tasks: tasks}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	taskDefinitions, err := describeTaskDefinitions(ctx, clients.ECS, tasksPerService)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for _, st := range tasksPerService {
+		for _, task := range st.tasks {
+			resources := extractTaskPorts(st.service, task, taskDefinitions[aws.ToString(task.TaskDefinitionArn)], containerNames)
+			result = append(result, resources...)
+		}
+	}
+
+	slog.Info("ECS discovery completed", "total_ports", len(result))
+	return result, nil
+}
+
+// listClusters lists the ARNs of all clusters in the region, following NextToken
+func listClusters(ctx context.Context, client ECSAPI) ([]string, error) {
+	var result []string
+	var nextToken *string
+
+	for {
+		input := &ecs.ListClustersInput{
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *ecs.ListClustersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListClusters API call: %v", r)
+				}
+			}()
+			resp, err = client.ListClusters(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ECS clusters: %w", err)
+		}
+		result = append(result, resp.ClusterArns...)
+
+		if aws.ToString(resp.NextToken) == "" {
+			return result, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// listClusterServices returns the services of a cluster with their tags, ordered by name
+func listClusterServices(ctx context.Context, client ECSAPI, cluster string) ([]ecstypes.Service, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		input := &ecs.ListServicesInput{
+			Cluster:    aws.String(cluster),
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *ecs.ListServicesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListServices API call: %v", r)
+				}
+			}()
+			resp, err = client.ListServices(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services of ECS cluster %s: %w", cluster, err)
+		}
+		arns = append(arns, resp.ServiceArns...)
+
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	var result []ecstypes.Service
+	for start := 0; start < len(arns); start += describeServicesBatchSize {
+		end := min(start+describeServicesBatchSize, len(arns))
+
+		// Catch panic and convert to error
+		var resp *ecs.DescribeServicesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeServices API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+				Cluster:  aws.String(cluster),
+				Services: arns[start:end],
+				Include:  []ecstypes.ServiceField{ecstypes.ServiceFieldTags},
+			})
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe services of ECS cluster %s: %w", cluster, err)
+		}
+		result = append(result, resp.Services...)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return aws.ToString(result[i].ServiceName) < aws.ToString(result[j].ServiceName)
+	})
+	return result, nil
+}
+
+// listServiceTasks returns the running tasks of a service ordered by task ARN
+func listServiceTasks(ctx context.Context, client ECSAPI, cluster, serviceName string) ([]ecstypes.Task, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		input := &ecs.ListTasksInput{
+			Cluster:       aws.String(cluster),
+			ServiceName:   aws.String(serviceName),
+			DesiredStatus: ecstypes.DesiredStatusRunning,
+			MaxResults:    aws.Int32(listPageSize),
+			NextToken:     nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *ecs.ListTasksOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListTasks API call: %v", r)
+				}
+			}()
+			resp, err = client.ListTasks(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks of ECS service %s: %w", serviceName, err)
+		}
+		arns = append(arns, resp.TaskArns...)
+
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	var result []ecstypes.Task
+	for start := 0; start < len(arns); start += describeTasksBatchSize {
+		end := min(start+describeTasksBatchSize, len(arns))
+
+		// Catch panic and convert to error
+		var resp *ecs.DescribeTasksOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeTasks API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+				Cluster: aws.String(cluster),
+				Tasks:   arns[start:end],
+			})
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe tasks of ECS service %s: %w", serviceName, err)
+		}
+		for _, task := range resp.Tasks {
+			if aws.ToString(task.LastStatus) != taskStatusRunning {
+				slog.Debug("Skipping ECS task",
+					"task_arn", aws.ToString(task.TaskArn),
+					"last_status", aws.ToString(task.LastStatus))
+				continue
+			}
+			result = append(result, task)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return aws.ToString(result[i].TaskArn) < aws.ToString(result[j].TaskArn)
+	})
+	return result, nil
+}
+
+// describeTaskDefinitions describes each task definition used by the tasks once,
+// keyed by task definition ARN
+func describeTaskDefinitions(ctx context.Context, client ECSAPI, tasksPerService []serviceTasks) (map[string]ecstypes.TaskDefinition, error) {
+	var arns []string
+	seen := make(map[string]bool)
+	for _, st := range tasksPerService {
+		for _, task := range st.tasks {
+			arn := aws.ToString(task.TaskDefinitionArn)
+			if !seen[arn] {
+				seen[arn] = true
+				arns = append(arns, arn)
+			}
+		}
+	}
+
+	definitions, err := providers.ParallelMap(ctx, arns, describeConcurrency, func(ctx context.Context, arn string) (ecstypes.TaskDefinition, error) {
+		// Catch panic and convert to error
+		var resp *ecs.DescribeTaskDefinitionOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeTaskDefinition API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+				TaskDefinition: aws.String(arn),
+			})
+		}()
+
+		if err != nil {
+			return ecstypes.TaskDefinition{}, fmt.Errorf("failed to describe task definition %s: %w", arn, err)
+		}
+		if resp.TaskDefinition == nil {
+			return ecstypes.TaskDefinition{}, nil
+		}
+		return *resp.TaskDefinition, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]ecstypes.TaskDefinition, len(arns))
+	for i, arn := range arns {
+		result[arn] = definitions[i]
+	}
+	return result, nil
+}
+
+// taskPrivateIP returns the private IPv4 address of the task ENI, or an empty
+// string when the task does not use the awsvpc network mode
+func taskPrivateIP(task ecstypes.Task) string {
+	for _, attachment := range task.Attachments {
+		if aws.ToString(attachment.Type) != eniAttachmentType {
+			continue
+		}
+		for _, detail := range attachment.Details {
+			if aws.ToString(detail.Name) == privateIPv4AddressDetail {
+				return aws.ToString(detail.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// extractTaskPorts returns a resource per container port of a task. Containers not in
+// containerNames are left out when containerNames is not empty.
+func extractTaskPorts(service ecstypes.Service, task ecstypes.Task, taskDefinition ecstypes.TaskDefinition, containerNames []string) []providers.Resource {
+	taskARN := aws.ToString(task.TaskArn)
+
+	ip := taskPrivateIP(task)
+	if ip == "" {
+		slog.Warn("ECS task has no ENI private IP address", "task_arn", taskARN)
+		return nil
+	}
+
+	clusterARN := aws.ToString(service.ClusterArn)
+	tags := tagsToMap(service.Tags)
+
+	var result []providers.Resource
+	for _, container := range taskDefinition.ContainerDefinitions {
+		containerName := aws.ToString(container.Name)
+		if len(containerNames) > 0 && !slices.Contains(containerNames, containerName) {
+			continue
+		}
+
+		for _, mapping := range container.PortMappings {
+			// Port ranges have no single container port
+			if mapping.ContainerPort == nil {
+				continue
+			}
+			port := int(aws.ToInt32(mapping.ContainerPort))
+
+			slog.Debug("Extracted task port",
+				"host", ip,
+				"port", port,
+				"service_name", aws.ToString(service.ServiceName),
+				"container_name", containerName)
+
+			result = append(result, providers.Resource{
+				Host: ip,
+				Port: port,
+				Tags: tags,
+				Metadata: map[string]interface{}{
+					"ClusterName":            awsutil.ResourceIDFromARN(clusterARN),
+					"ClusterArn":             clusterARN,
+					"ServiceName":            aws.ToString(service.ServiceName),
+					"TaskArn":                taskARN,
+					"TaskID":                 awsutil.ResourceIDFromARN(taskARN),
+					"TaskDefinitionFamily":   aws.ToString(taskDefinition.Family),
+					"TaskDefinitionRevision": int(taskDefinition.Revision),
+					"ContainerName":          containerName,
+					"PortName":               aws.ToString(mapping.Name),
+					"Protocol":               string(mapping.Protocol),
+					"AvailabilityZone":       aws.ToString(task.AvailabilityZone),
+					"LaunchType":             string(task.LaunchType),
+					"HealthStatus":           string(task.HealthStatus),
+				},
+			})
+		}
+	}
+	return result
+}
+
+// tagsToMap converts ECS tags to a map
+func tagsToMap(tags []ecstypes.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[*tag.Key] = *tag.Value
+		}
+	}
+	return result
+}
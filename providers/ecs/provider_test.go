@@ -0,0 +1,465 @@
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClusterARN = "arn:aws:ecs:ap-northeast-1:123456789012:cluster/production"
+	testTaskDefARN = "arn:aws:ecs:ap-northeast-1:123456789012:task-definition/api:42"
+)
+
+// MockECSClient is a mock implementation of ECSAPI
+type MockECSClient struct {
+	mock.Mock
+}
+
+func (m *MockECSClient) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.ListClustersOutput), args.Error(1)
+}
+
+func (m *MockECSClient) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.ListServicesOutput), args.Error(1)
+}
+
+func (m *MockECSClient) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.DescribeServicesOutput), args.Error(1)
+}
+
+func (m *MockECSClient) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.ListTasksOutput), args.Error(1)
+}
+
+func (m *MockECSClient) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.DescribeTasksOutput), args.Error(1)
+}
+
+func (m *MockECSClient) DescribeTaskDefinition(ctx context.Context, params *ecs.DescribeTaskDefinitionInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTaskDefinitionOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ecs.DescribeTaskDefinitionOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client ECSAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{ECS: client}, nil
+	})
+}
+
+// newService returns an active service of the test cluster with the given tags
+func newService(name string, kv ...string) ecstypes.Service {
+	service := ecstypes.Service{
+		ServiceName: aws.String(name),
+		ServiceArn:  aws.String("arn:aws:ecs:ap-northeast-1:123456789012:service/production/" + name),
+		ClusterArn:  aws.String(testClusterARN),
+		Status:      aws.String("ACTIVE"),
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		service.Tags = append(service.Tags, ecstypes.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return service
+}
+
+// newTask returns a task of the test task definition with the given ENI IP address
+func newTask(id, ip, lastStatus string) ecstypes.Task {
+	task := ecstypes.Task{
+		TaskArn:           aws.String("arn:aws:ecs:ap-northeast-1:123456789012:task/production/" + id),
+		ClusterArn:        aws.String(testClusterARN),
+		TaskDefinitionArn: aws.String(testTaskDefARN),
+		LastStatus:        aws.String(lastStatus),
+		AvailabilityZone:  aws.String("ap-northeast-1a"),
+		LaunchType:        ecstypes.LaunchTypeFargate,
+		HealthStatus:      ecstypes.HealthStatusHealthy,
+	}
+	if ip != "" {
+		task.Attachments = []ecstypes.Attachment{
+			{
+				Type:   aws.String("ElasticNetworkInterface"),
+				Status: aws.String("ATTACHED"),
+				Details: []ecstypes.KeyValuePair{
+					{Name: aws.String("subnetId"), Value: aws.String("subnet-0123")},
+					{Name: aws.String("privateIPv4Address"), Value: aws.String(ip)},
+				},
+			},
+		}
+	}
+	return task
+}
+
+// taskDefinitionOutput returns the test task definition with an app and a sidecar container
+func taskDefinitionOutput() *ecs.DescribeTaskDefinitionOutput {
+	return &ecs.DescribeTaskDefinitionOutput{
+		TaskDefinition: &ecstypes.TaskDefinition{
+			TaskDefinitionArn: aws.String(testTaskDefARN),
+			Family:            aws.String("api"),
+			Revision:          42,
+			NetworkMode:       ecstypes.NetworkModeAwsvpc,
+			ContainerDefinitions: []ecstypes.ContainerDefinition{
+				{
+					Name: aws.String("app"),
+					PortMappings: []ecstypes.PortMapping{
+						{ContainerPort: aws.Int32(8080), Name: aws.String("http"), Protocol: ecstypes.TransportProtocolTcp},
+						{ContainerPort: aws.Int32(9090), Name: aws.String("metrics"), Protocol: ecstypes.TransportProtocolTcp},
+					},
+				},
+				{
+					Name: aws.String("envoy"),
+					PortMappings: []ecstypes.PortMapping{
+						{ContainerPort: aws.Int32(9901), Protocol: ecstypes.TransportProtocolTcp},
+					},
+				},
+				{Name: aws.String("log-router")},
+			},
+		},
+	}
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "ecs_task", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"clusters":        []interface{}{"production"},
+			"container_names": []interface{}{"app"},
+			"health_status":   []interface{}{"HEALTHY", "UNKNOWN"},
+		},
+	}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"clusters": "production"},
+	})
+	assert.EqualError(t, err, "filters.clusters must be a list of strings")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"container_names": []interface{}{1}},
+	})
+	assert.EqualError(t, err, "filters.container_names must be a list of strings")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"health_status": []interface{}{"healthy"}},
+	})
+	assert.EqualError(t, err, `filters.health_status contains unknown health status "healthy"`)
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListClusters", mock.Anything, mock.MatchedBy(func(input *ecs.ListClustersInput) bool {
+			return input.NextToken == nil
+		}), mock.Anything).Return(&ecs.ListClustersOutput{
+			ClusterArns: []string{testClusterARN},
+			NextToken:   aws.String("next"),
+		}, nil).Once()
+		mockECS.On("ListClusters", mock.Anything, mock.MatchedBy(func(input *ecs.ListClustersInput) bool {
+			return aws.ToString(input.NextToken) == "next"
+		}), mock.Anything).Return(&ecs.ListClustersOutput{}, nil).Once()
+
+		mockECS.On("ListServices", mock.Anything, mock.MatchedBy(func(input *ecs.ListServicesInput) bool {
+			return aws.ToString(input.Cluster) == testClusterARN
+		}), mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{
+				"arn:aws:ecs:ap-northeast-1:123456789012:service/production/worker",
+				"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api",
+				"arn:aws:ecs:ap-northeast-1:123456789012:service/production/batch",
+			},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.MatchedBy(func(input *ecs.DescribeServicesInput) bool {
+			return len(input.Services) == 3 &&
+				len(input.Include) == 1 && input.Include[0] == ecstypes.ServiceFieldTags
+		}), mock.Anything).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{
+				newService("worker", "Environment", "staging"),
+				newService("api", "Environment", "production", "Team", "backend"),
+				func() ecstypes.Service {
+					service := newService("batch", "Environment", "production")
+					service.Status = aws.String("DRAINING")
+					return service
+				}(),
+			},
+		}, nil)
+
+		mockECS.On("ListTasks", mock.Anything, mock.MatchedBy(func(input *ecs.ListTasksInput) bool {
+			return aws.ToString(input.ServiceName) == "api" &&
+				input.DesiredStatus == ecstypes.DesiredStatusRunning && input.NextToken == nil
+		}), mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns:  []string{"arn:aws:ecs:ap-northeast-1:123456789012:task/production/bbb"},
+			NextToken: aws.String("next"),
+		}, nil).Once()
+		mockECS.On("ListTasks", mock.Anything, mock.MatchedBy(func(input *ecs.ListTasksInput) bool {
+			return aws.ToString(input.ServiceName) == "api" && aws.ToString(input.NextToken) == "next"
+		}), mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{
+				"arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa",
+				"arn:aws:ecs:ap-northeast-1:123456789012:task/production/ccc",
+			},
+		}, nil).Once()
+		mockECS.On("DescribeTasks", mock.Anything, mock.MatchedBy(func(input *ecs.DescribeTasksInput) bool {
+			return aws.ToString(input.Cluster) == testClusterARN && len(input.Tasks) == 3
+		}), mock.Anything).Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{
+				newTask("bbb", "10.0.2.20", "RUNNING"),
+				newTask("aaa", "10.0.1.10", "RUNNING"),
+				newTask("ccc", "10.0.3.30", "PROVISIONING"),
+			},
+		}, nil)
+
+		mockECS.On("DescribeTaskDefinition", mock.Anything, &ecs.DescribeTaskDefinitionInput{
+			TaskDefinition: aws.String(testTaskDefARN),
+		}, mock.Anything).Return(taskDefinitionOutput(), nil).Once()
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"Environment": "production"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 6)
+
+		resource := result[0]
+		assert.Equal(t, "10.0.1.10", resource.Host)
+		assert.Equal(t, 8080, resource.Port)
+		assert.Equal(t, "production", resource.Tags["Environment"])
+		assert.Equal(t, "backend", resource.Tags["Team"])
+		assert.Equal(t, "production", resource.Metadata["ClusterName"])
+		assert.Equal(t, testClusterARN, resource.Metadata["ClusterArn"])
+		assert.Equal(t, "api", resource.Metadata["ServiceName"])
+		assert.Equal(t, "arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa", resource.Metadata["TaskArn"])
+		assert.Equal(t, "aaa", resource.Metadata["TaskID"])
+		assert.Equal(t, "api", resource.Metadata["TaskDefinitionFamily"])
+		assert.Equal(t, 42, resource.Metadata["TaskDefinitionRevision"])
+		assert.Equal(t, "app", resource.Metadata["ContainerName"])
+		assert.Equal(t, "http", resource.Metadata["PortName"])
+		assert.Equal(t, "tcp", resource.Metadata["Protocol"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "FARGATE", resource.Metadata["LaunchType"])
+		assert.Equal(t, "HEALTHY", resource.Metadata["HealthStatus"])
+
+		assert.Equal(t, 9090, result[1].Port)
+		assert.Equal(t, "metrics", result[1].Metadata["PortName"])
+		assert.Equal(t, 9901, result[2].Port)
+		assert.Equal(t, "envoy", result[2].Metadata["ContainerName"])
+		assert.Equal(t, "", result[2].Metadata["PortName"])
+		assert.Equal(t, "10.0.2.20", result[3].Host)
+		assert.Equal(t, "bbb", result[3].Metadata["TaskID"])
+
+		mockECS.AssertExpectations(t)
+		mockECS.AssertNumberOfCalls(t, "ListTasks", 2)
+	})
+
+	t.Run("clusters and container names filters", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListServices", mock.Anything, mock.MatchedBy(func(input *ecs.ListServicesInput) bool {
+			return aws.ToString(input.Cluster) == "production"
+		}), mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api"},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{newService("api")},
+		}, nil)
+		mockECS.On("ListTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa"},
+		}, nil)
+		mockECS.On("DescribeTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{newTask("aaa", "10.0.1.10", "RUNNING")},
+		}, nil)
+		mockECS.On("DescribeTaskDefinition", mock.Anything, mock.Anything, mock.Anything).Return(taskDefinitionOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"clusters":        []interface{}{"production"},
+				"container_names": []interface{}{"envoy"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, 9901, result[0].Port)
+		assert.Equal(t, "envoy", result[0].Metadata["ContainerName"])
+		mockECS.AssertNotCalled(t, "ListClusters", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("health status filter", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		unhealthy := newTask("bbb", "10.0.1.11", "RUNNING")
+		unhealthy.HealthStatus = ecstypes.HealthStatusUnhealthy
+
+		mockECS.On("ListServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api"},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{newService("api")},
+		}, nil)
+		mockECS.On("ListTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{
+				"arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa",
+				"arn:aws:ecs:ap-northeast-1:123456789012:task/production/bbb",
+			},
+		}, nil)
+		mockECS.On("DescribeTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{newTask("aaa", "10.0.1.10", "RUNNING"), unhealthy},
+		}, nil)
+		mockECS.On("DescribeTaskDefinition", mock.Anything, mock.Anything, mock.Anything).Return(taskDefinitionOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"clusters":        []interface{}{"production"},
+				"container_names": []interface{}{"envoy"},
+				"health_status":   []interface{}{"HEALTHY"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "10.0.1.10", result[0].Host)
+		assert.Equal(t, "HEALTHY", result[0].Metadata["HealthStatus"])
+	})
+
+	t.Run("task without ENI is skipped", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api"},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{newService("api")},
+		}, nil)
+		mockECS.On("ListTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa"},
+		}, nil)
+		mockECS.On("DescribeTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{newTask("aaa", "", "RUNNING")},
+		}, nil)
+		mockECS.On("DescribeTaskDefinition", mock.Anything, mock.Anything, mock.Anything).Return(taskDefinitionOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"clusters": []interface{}{"production"}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("no services", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListClustersOutput{
+			ClusterArns: []string{testClusterARN},
+		}, nil)
+		mockECS.On("ListServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListServicesOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockECS.AssertNotCalled(t, "DescribeServices", mock.Anything, mock.Anything, mock.Anything)
+		mockECS.AssertNotCalled(t, "ListTasks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("list clusters error", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list ECS clusters")
+	})
+
+	t.Run("describe services error", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api"},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"clusters": []interface{}{"production"}},
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe services of ECS cluster production")
+	})
+
+	t.Run("describe task definition error", func(t *testing.T) {
+		mockECS := new(MockECSClient)
+		provider := newTestProvider(mockECS)
+
+		mockECS.On("ListServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListServicesOutput{
+			ServiceArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:service/production/api"},
+		}, nil)
+		mockECS.On("DescribeServices", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{newService("api")},
+		}, nil)
+		mockECS.On("ListTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.ListTasksOutput{
+			TaskArns: []string{"arn:aws:ecs:ap-northeast-1:123456789012:task/production/aaa"},
+		}, nil)
+		mockECS.On("DescribeTasks", mock.Anything, mock.Anything, mock.Anything).Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{newTask("aaa", "10.0.1.10", "RUNNING")},
+		}, nil)
+		mockECS.On("DescribeTaskDefinition", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"clusters": []interface{}{"production"}},
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe task definition "+testTaskDefARN)
+	})
+}
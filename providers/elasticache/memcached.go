@@ -0,0 +1,154 @@
+package elasticache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const memcachedProviderType = "elasticache_memcached"
+
+// MemcachedProvider implements the providers.Provider interface for ElastiCache Memcached
+type MemcachedProvider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// NewMemcachedProvider creates a new ElastiCache Memcached provider
+func NewMemcachedProvider() *MemcachedProvider {
+	return NewMemcachedProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewMemcachedProviderWithClientFactory creates a new ElastiCache Memcached provider
+// that obtains its AWS clients from the given factory
+func NewMemcachedProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *MemcachedProvider {
+	return &MemcachedProvider{
+		newClients: newClients,
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *MemcachedProvider) Type() string {
+	return memcachedProviderType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *MemcachedProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves ElastiCache Memcached nodes based on the configuration
+func (p *MemcachedProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting ElastiCache Memcached discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	// Get cache clusters by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, cacheClusterResourceType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourceTagMappings) == 0 {
+		slog.Info("No cache clusters found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found cache clusters by tags", "count", len(resourceTagMappings))
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	arns := make([]string, 0, len(resourceTagMappings))
+	for _, mapping := range resourceTagMappings {
+		arns = append(arns, aws.ToString(mapping.ResourceARN))
+	}
+
+	// Describe cache clusters in parallel; results keep the order of the ARNs
+	clustersPerARN, err := providers.ParallelMap(ctx, arns, describeConcurrency, func(ctx context.Context, arn string) ([]elasticachetypes.CacheCluster, error) {
+		return describeCacheCluster(ctx, clients.ElastiCache, awsutil.ResourceIDFromARN(arn))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for i, clusters := range clustersPerARN {
+		for _, cluster := range clusters {
+			// The cluster resource type also covers Redis nodes
+			if aws.ToString(cluster.Engine) != "memcached" {
+				slog.Debug("Skipping non-Memcached cache cluster",
+					"cache_cluster_id", aws.ToString(cluster.CacheClusterId),
+					"engine", aws.ToString(cluster.Engine))
+				continue
+			}
+
+			nodes := extractNodesFromMemcachedCluster(cluster, arnToTags[arns[i]])
+			slog.Debug("Extracted nodes from cache cluster",
+				"cache_cluster_id", aws.ToString(cluster.CacheClusterId),
+				"nodes_count", len(nodes))
+			result = append(result, nodes...)
+		}
+	}
+
+	slog.Info("ElastiCache Memcached discovery completed", "total_nodes", len(result))
+	return result, nil
+}
+
+// extractNodesFromMemcachedCluster extracts all cache nodes from a Memcached cluster
+func extractNodesFromMemcachedCluster(cluster elasticachetypes.CacheCluster, tags map[string]string) []providers.Resource {
+	var result []providers.Resource
+	clusterID := aws.ToString(cluster.CacheClusterId)
+
+	var configurationEndpoint string
+	if cluster.ConfigurationEndpoint != nil {
+		configurationEndpoint = fmt.Sprintf("%s:%d", aws.ToString(cluster.ConfigurationEndpoint.Address), aws.ToInt32(cluster.ConfigurationEndpoint.Port))
+	}
+
+	for _, node := range cluster.CacheNodes {
+		if node.Endpoint == nil {
+			slog.Warn("Cache node has no endpoint",
+				"cache_cluster_id", clusterID,
+				"cache_node_id", aws.ToString(node.CacheNodeId))
+			continue
+		}
+
+		resource := providers.Resource{
+			Host: aws.ToString(node.Endpoint.Address),
+			Port: int(aws.ToInt32(node.Endpoint.Port)),
+			Tags: tags,
+			Metadata: map[string]interface{}{
+				"CacheClusterID":        clusterID,
+				"CacheNodeID":           aws.ToString(node.CacheNodeId),
+				"AvailabilityZone":      aws.ToString(node.CustomerAvailabilityZone),
+				"CacheNodeType":         aws.ToString(cluster.CacheNodeType),
+				"EngineVersion":         aws.ToString(cluster.EngineVersion),
+				"ConfigurationEndpoint": configurationEndpoint,
+			},
+		}
+
+		slog.Debug("Extracted node",
+			"host", resource.Host,
+			"port", resource.Port,
+			"cache_cluster_id", clusterID,
+			"cache_node_id", aws.ToString(node.CacheNodeId))
+
+		result = append(result, resource)
+	}
+
+	return result
+}
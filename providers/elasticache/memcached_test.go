@@ -0,0 +1,202 @@
+package elasticache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMemcachedProvider creates a Memcached provider whose client factory always returns the given mocks
+func newTestMemcachedProvider(tagging ResourceGroupsTaggingAPI, elastiCache ElastiCacheAPI) *MemcachedProvider {
+	return NewMemcachedProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			ElastiCache: elastiCache,
+			Tagging:     tagging,
+		}, nil
+	})
+}
+
+func TestMemcachedProvider_Type(t *testing.T) {
+	provider := NewMemcachedProvider()
+	assert.Equal(t, "elasticache_memcached", provider.Type())
+}
+
+func TestMemcachedProvider_ValidateConfig(t *testing.T) {
+	provider := NewMemcachedProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "us-east-1"}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "region is required")
+}
+
+func TestMemcachedProvider_Discover(t *testing.T) {
+	cfg := providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Environment": "production",
+			},
+		},
+	}
+
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestMemcachedProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("elasticache:cluster"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{
+					ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:cluster:my-memcached"),
+					Tags: []taggingtypes.Tag{
+						{Key: aws.String("Environment"), Value: aws.String("production")},
+					},
+				},
+			},
+		}, nil)
+
+		mockElastiCache.On("DescribeCacheClusters", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeCacheClustersInput) bool {
+			return aws.ToString(input.CacheClusterId) == "my-memcached" && aws.ToBool(input.ShowCacheNodeInfo)
+		}), mock.Anything).Return(&elasticache.DescribeCacheClustersOutput{
+			CacheClusters: []elasticachetypes.CacheCluster{
+				{
+					CacheClusterId: aws.String("my-memcached"),
+					Engine:         aws.String("memcached"),
+					EngineVersion:  aws.String("1.6.22"),
+					CacheNodeType:  aws.String("cache.t4g.micro"),
+					ConfigurationEndpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("my-memcached.abc123.cfg.apne1.cache.amazonaws.com"),
+						Port:    aws.Int32(11211),
+					},
+					CacheNodes: []elasticachetypes.CacheNode{
+						{
+							CacheNodeId:              aws.String("0001"),
+							CustomerAvailabilityZone: aws.String("ap-northeast-1a"),
+							Endpoint: &elasticachetypes.Endpoint{
+								Address: aws.String("my-memcached.abc123.0001.apne1.cache.amazonaws.com"),
+								Port:    aws.Int32(11211),
+							},
+						},
+						{
+							CacheNodeId:              aws.String("0002"),
+							CustomerAvailabilityZone: aws.String("ap-northeast-1c"),
+							Endpoint: &elasticachetypes.Endpoint{
+								Address: aws.String("my-memcached.abc123.0002.apne1.cache.amazonaws.com"),
+								Port:    aws.Int32(11211),
+							},
+						},
+						{
+							// Nodes that are still being created have no endpoint
+							CacheNodeId: aws.String("0003"),
+						},
+					},
+				},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		assert.Equal(t, "my-memcached.abc123.0001.apne1.cache.amazonaws.com", result[0].Host)
+		assert.Equal(t, 11211, result[0].Port)
+		assert.Equal(t, "production", result[0].Tags["Environment"])
+		assert.Equal(t, "my-memcached", result[0].Metadata["CacheClusterID"])
+		assert.Equal(t, "0001", result[0].Metadata["CacheNodeID"])
+		assert.Equal(t, "ap-northeast-1a", result[0].Metadata["AvailabilityZone"])
+		assert.Equal(t, "cache.t4g.micro", result[0].Metadata["CacheNodeType"])
+		assert.Equal(t, "1.6.22", result[0].Metadata["EngineVersion"])
+		assert.Equal(t, "my-memcached.abc123.cfg.apne1.cache.amazonaws.com:11211", result[0].Metadata["ConfigurationEndpoint"])
+
+		assert.Equal(t, "0002", result[1].Metadata["CacheNodeID"])
+		assert.Equal(t, "ap-northeast-1c", result[1].Metadata["AvailabilityZone"])
+
+		mockTagging.AssertExpectations(t)
+		mockElastiCache.AssertExpectations(t)
+	})
+
+	t.Run("skips non-memcached clusters", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestMemcachedProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:cluster:my-redis-001")},
+			},
+		}, nil)
+
+		mockElastiCache.On("DescribeCacheClusters", mock.Anything, mock.Anything, mock.Anything).Return(&elasticache.DescribeCacheClustersOutput{
+			CacheClusters: []elasticachetypes.CacheCluster{
+				{
+					CacheClusterId: aws.String("my-redis-001"),
+					Engine:         aws.String("redis"),
+					CacheNodes: []elasticachetypes.CacheNode{
+						{
+							CacheNodeId: aws.String("0001"),
+							Endpoint: &elasticachetypes.Endpoint{
+								Address: aws.String("my-redis-001.abc123.0001.apne1.cache.amazonaws.com"),
+								Port:    aws.Int32(6379),
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestMemcachedProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockElastiCache.AssertNotCalled(t, "DescribeCacheClusters", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestMemcachedProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:cluster:my-memcached")},
+			},
+		}, nil)
+		mockElastiCache.On("DescribeCacheClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("API error"))
+
+		result, err := provider.Discover(ctx, cfg)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to describe cache cluster my-memcached")
+	})
+}
@@ -7,25 +7,50 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
 	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
-	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
 	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
 )
 
 const providerType = "elasticache_redis"
 
+// includeStandaloneFilter enables discovery of cache clusters outside replication groups
+const includeStandaloneFilter = "include_standalone"
+
+// replicationGroupResourceType is the Resource Groups Tagging API type of replication groups
+const replicationGroupResourceType = "elasticache:replicationgroup"
+
+// cacheClusterResourceType is the Resource Groups Tagging API type of cache clusters
+const cacheClusterResourceType = "elasticache:cluster"
+
+const (
+	// describeConcurrency is the maximum number of describe calls in flight
+	describeConcurrency = 5
+	// batchDescribeThreshold is the number of matching replication groups above which
+	// all groups are listed in pages instead of being described one by one
+	batchDescribeThreshold = 20
+	// describePageSize is the MaxRecords value used when listing replication groups
+	describePageSize = 100
+)
+
 // Provider implements the providers.Provider interface for ElastiCache Redis
 type Provider struct {
-	elasticacheClient ElastiCacheAPI
-	taggingClient     ResourceGroupsTaggingAPI
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	ElastiCache ElastiCacheAPI
+	Tagging     ResourceGroupsTaggingAPI
 }
 
 // ElastiCacheAPI defines the ElastiCache API interface
 type ElastiCacheAPI interface {
 	DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error)
+	DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error)
+	DescribeServerlessCaches(ctx context.Context, params *elasticache.DescribeServerlessCachesInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeServerlessCachesOutput, error)
 }
 
 // ResourceGroupsTaggingAPI defines the Resource Groups Tagging API interface
@@ -35,7 +60,23 @@ type ResourceGroupsTaggingAPI interface {
 
 // NewProvider creates a new ElastiCache provider
 func NewProvider() *Provider {
-	return &Provider{}
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new ElastiCache provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		ElastiCache: elasticache.NewFromConfig(awsCfg),
+		Tagging:     resourcegroupstaggingapi.NewFromConfig(awsCfg),
+	}
 }
 
 // Type returns the resource type handled by this provider
@@ -45,58 +86,53 @@ func (p *Provider) Type() string {
 
 // ValidateConfig checks if the provider configuration is valid
 func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
-	if cfg.Region == "" {
-		return fmt.Errorf("region is required")
-	}
-
-	// Check if filters contains tags
-	if cfg.Filters != nil {
-		if _, ok := cfg.Filters["tags"]; ok {
-			// tags should be a map
-			if _, ok := cfg.Filters["tags"].(map[string]interface{}); !ok {
-				return fmt.Errorf("filters.tags must be a map")
-			}
-		}
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
 	}
 
-	return nil
+	_, err := providers.BoolFilter(cfg.Filters, includeStandaloneFilter)
+	return err
 }
 
 // Discover retrieves ElastiCache Redis resources based on the configuration
 func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
-	slog.Debug("Starting ElastiCache Redis discovery", "region", cfg.Region)
+	slog.Debug("Starting ElastiCache Redis discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
 
 	if err := p.ValidateConfig(cfg); err != nil {
 		return nil, err
 	}
+	includeStandalone, _ := providers.BoolFilter(cfg.Filters, includeStandaloneFilter)
 
-	// Load AWS config
-	slog.Debug("Loading AWS configuration", "region", cfg.Region)
-	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
+	clients, err := p.newClients(ctx, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w (check AWS credentials and configuration)", err)
+		return nil, err
 	}
 
-	// Initialize clients if not set (for testing, they can be injected)
-	if p.taggingClient == nil {
-		p.taggingClient = resourcegroupstaggingapi.NewFromConfig(awsCfg)
-		if p.taggingClient == nil {
-			return nil, fmt.Errorf("failed to create Resource Groups Tagging API client")
-		}
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	result, err := discoverReplicationGroupNodes(ctx, clients, tags)
+	if err != nil {
+		return nil, err
 	}
-	if p.elasticacheClient == nil {
-		p.elasticacheClient = elasticache.NewFromConfig(awsCfg)
-		if p.elasticacheClient == nil {
-			return nil, fmt.Errorf("failed to create ElastiCache client")
+
+	if includeStandalone {
+		nodes, err := discoverStandaloneNodes(ctx, clients, tags)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, nodes...)
 	}
 
-	// Extract tag filters from config
-	tags := extractTagFilters(cfg.Filters)
-	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+	slog.Info("ElastiCache Redis discovery completed", "total_nodes", len(result))
+	return result, nil
+}
 
+// discoverReplicationGroupNodes retrieves the nodes of replication groups matching the tags
+func discoverReplicationGroupNodes(ctx context.Context, clients *Clients, tags map[string]string) ([]providers.Resource, error) {
 	// Get replication groups by tags
-	resourceTagMappings, err := p.getReplicationGroupsByTags(ctx, tags)
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, replicationGroupResourceType, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +145,7 @@ func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) (
 	slog.Info("Found replication groups by tags", "count", len(resourceTagMappings))
 
 	// Build ARN to tags map
-	arnToTags := buildARNToTagsMap(resourceTagMappings)
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
 
 	// Extract replication group IDs
 	var replicationGroupARNs []string
@@ -125,156 +161,209 @@ func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) (
 		idToARN[replicationGroupIDs[i]] = arn
 	}
 
-	// Ensure elasticache client is initialized
-	if p.elasticacheClient == nil {
-		return nil, fmt.Errorf("elasticache client is not initialized")
+	// Describe replication groups; results keep the order of the IDs
+	groupsPerID, err := describeReplicationGroups(ctx, clients.ElastiCache, replicationGroupIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	// Describe replication groups and extract nodes
 	var result []providers.Resource
-	for _, id := range replicationGroupIDs {
-		slog.Debug("Describing replication group", "replication_group_id", id)
-
-		descInput := &elasticache.DescribeReplicationGroupsInput{
-			ReplicationGroupId: aws.String(id),
-		}
-
-		// Catch panic and convert to error
-		var resp *elasticache.DescribeReplicationGroupsOutput
-		var err error
-		func() {
-			defer func() {
-				if r := recover(); r != nil {
-					err = fmt.Errorf("panic occurred during DescribeReplicationGroups API call: %v", r)
-				}
-			}()
-			resp, err = p.elasticacheClient.DescribeReplicationGroups(ctx, descInput)
-		}()
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to describe replication group %s: %w", id, err)
-		}
-
-		if len(resp.ReplicationGroups) == 0 {
+	for i, id := range replicationGroupIDs {
+		replicationGroups := groupsPerID[i]
+		if len(replicationGroups) == 0 {
 			slog.Warn("No replication group details found", "replication_group_id", id)
 			continue
 		}
 
 		slog.Debug("Retrieved replication group details",
 			"replication_group_id", id,
-			"node_groups_count", len(resp.ReplicationGroups[0].NodeGroups))
+			"node_groups_count", len(replicationGroups[0].NodeGroups))
 
 		// Get tags for this ARN (pass all tags as-is)
 		arn := idToARN[id]
 		clusterTags := arnToTags[arn]
 
 		// Extract nodes from replication groups
-		nodes := extractNodesFromReplicationGroups(resp.ReplicationGroups, id, clusterTags)
+		nodes := extractNodesFromReplicationGroups(replicationGroups, id, clusterTags)
 		slog.Debug("Extracted nodes from replication group",
 			"replication_group_id", id,
 			"nodes_count", len(nodes))
 		result = append(result, nodes...)
 	}
 
-	slog.Info("ElastiCache Redis discovery completed", "total_nodes", len(result))
 	return result, nil
 }
 
-// extractTagFilters extracts tag filters from the filters map
-func extractTagFilters(filters map[string]interface{}) map[string]string {
-	tags := make(map[string]string)
-	if filters == nil {
-		return tags
+// discoverStandaloneNodes retrieves the nodes of Redis and Valkey cache clusters
+// matching the tags that do not belong to a replication group
+func discoverStandaloneNodes(ctx context.Context, clients *Clients, tags map[string]string) ([]providers.Resource, error) {
+	// Get cache clusters by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, cacheClusterResourceType, tags)
+	if err != nil {
+		return nil, err
 	}
 
-	if tagsInterface, ok := filters["tags"]; ok {
-		if tagsMap, ok := tagsInterface.(map[string]interface{}); ok {
-			for k, v := range tagsMap {
-				if strVal, ok := v.(string); ok {
-					tags[k] = strVal
-				}
+	if len(resourceTagMappings) == 0 {
+		slog.Info("No cache clusters found matching tag filters", "tags", tags)
+		return nil, nil
+	}
+
+	slog.Info("Found cache clusters by tags", "count", len(resourceTagMappings))
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	arns := make([]string, 0, len(resourceTagMappings))
+	for _, mapping := range resourceTagMappings {
+		arns = append(arns, aws.ToString(mapping.ResourceARN))
+	}
+
+	// Describe cache clusters in parallel; results keep the order of the ARNs
+	clustersPerARN, err := providers.ParallelMap(ctx, arns, describeConcurrency, func(ctx context.Context, arn string) ([]elasticachetypes.CacheCluster, error) {
+		return describeCacheCluster(ctx, clients.ElastiCache, awsutil.ResourceIDFromARN(arn))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for i, clusters := range clustersPerARN {
+		for _, cluster := range clusters {
+			// Members of replication groups are discovered through their group
+			if cluster.ReplicationGroupId != nil {
+				slog.Debug("Skipping cache cluster in replication group",
+					"cache_cluster_id", aws.ToString(cluster.CacheClusterId),
+					"replication_group_id", aws.ToString(cluster.ReplicationGroupId))
+				continue
 			}
+			if !isRedisEngine(aws.ToString(cluster.Engine)) {
+				slog.Debug("Skipping non-Redis cache cluster",
+					"cache_cluster_id", aws.ToString(cluster.CacheClusterId),
+					"engine", aws.ToString(cluster.Engine))
+				continue
+			}
+
+			nodes := extractNodesFromStandaloneCluster(cluster, arnToTags[arns[i]])
+			slog.Debug("Extracted nodes from standalone cache cluster",
+				"cache_cluster_id", aws.ToString(cluster.CacheClusterId),
+				"nodes_count", len(nodes))
+			result = append(result, nodes...)
+		}
+	}
+
+	return result, nil
+}
+
+// describeReplicationGroups returns the details of each replication group, in the order of ids.
+// Up to batchDescribeThreshold groups are described individually in parallel;
+// above that, all replication groups are listed page by page and joined by ID,
+// which needs far fewer API calls.
+func describeReplicationGroups(ctx context.Context, client ElastiCacheAPI, ids []string) ([][]elasticachetypes.ReplicationGroup, error) {
+	if len(ids) > batchDescribeThreshold {
+		slog.Debug("Listing all replication groups", "requested_count", len(ids))
+
+		all, err := listReplicationGroups(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list replication groups: %w", err)
+		}
+
+		byID := make(map[string]elasticachetypes.ReplicationGroup, len(all))
+		for _, rg := range all {
+			byID[aws.ToString(rg.ReplicationGroupId)] = rg
 		}
+
+		result := make([][]elasticachetypes.ReplicationGroup, len(ids))
+		for i, id := range ids {
+			if rg, ok := byID[id]; ok {
+				result[i] = []elasticachetypes.ReplicationGroup{rg}
+			}
+		}
+		return result, nil
 	}
 
-	return tags
+	return providers.ParallelMap(ctx, ids, describeConcurrency, func(ctx context.Context, id string) ([]elasticachetypes.ReplicationGroup, error) {
+		slog.Debug("Describing replication group", "replication_group_id", id)
+
+		resp, err := describeReplicationGroupsPage(ctx, client, &elasticache.DescribeReplicationGroupsInput{
+			ReplicationGroupId: aws.String(id),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe replication group %s: %w", id, err)
+		}
+		return resp.ReplicationGroups, nil
+	})
 }
 
-// getReplicationGroupsByTags retrieves replication groups filtered by tags
-func (p *Provider) getReplicationGroupsByTags(ctx context.Context, tags map[string]string) ([]taggingtypes.ResourceTagMapping, error) {
-	// Ensure tagging client is initialized
-	if p.taggingClient == nil {
-		return nil, fmt.Errorf("tagging client is not initialized")
+// listReplicationGroups lists all replication groups in the region, following Marker
+func listReplicationGroups(ctx context.Context, client ElastiCacheAPI) ([]elasticachetypes.ReplicationGroup, error) {
+	var result []elasticachetypes.ReplicationGroup
+	input := &elasticache.DescribeReplicationGroupsInput{
+		MaxRecords: aws.Int32(describePageSize),
 	}
 
-	tagFilters := buildTagFilters(tags)
-	slog.Debug("Calling GetResources API",
-		"resource_type", "elasticache:replicationgroup",
-		"tag_filters_count", len(tagFilters))
+	for {
+		resp, err := describeReplicationGroupsPage(ctx, client, input)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, resp.ReplicationGroups...)
+
+		slog.Debug("Retrieved replication groups page",
+			"page_count", len(resp.ReplicationGroups),
+			"total_count", len(result))
 
-	input := &resourcegroupstaggingapi.GetResourcesInput{
-		ResourceTypeFilters: []string{"elasticache:replicationgroup"},
-		TagFilters:          tagFilters,
+		if aws.ToString(resp.Marker) == "" {
+			return result, nil
+		}
+		input = &elasticache.DescribeReplicationGroupsInput{
+			MaxRecords: aws.Int32(describePageSize),
+			Marker:     resp.Marker,
+		}
 	}
+}
 
+// describeReplicationGroupsPage makes a single DescribeReplicationGroups call
+func describeReplicationGroupsPage(ctx context.Context, client ElastiCacheAPI, descInput *elasticache.DescribeReplicationGroupsInput) (*elasticache.DescribeReplicationGroupsOutput, error) {
 	// Catch panic and convert to error
-	var output *resourcegroupstaggingapi.GetResourcesOutput
+	var resp *elasticache.DescribeReplicationGroupsOutput
 	var err error
 	func() {
 		defer func() {
 			if r := recover(); r != nil {
-				err = fmt.Errorf("panic occurred during GetResources API call: %v", r)
+				err = fmt.Errorf("panic occurred during DescribeReplicationGroups API call: %v", r)
 			}
 		}()
-		output, err = p.taggingClient.GetResources(ctx, input)
+		resp, err = client.DescribeReplicationGroups(ctx, descInput)
 	}()
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get resources by tags: %w", err)
-	}
+	return resp, err
+}
 
-	slog.Debug("GetResources API call succeeded", "resources_count", len(output.ResourceTagMappingList))
+// describeCacheCluster describes a single cache cluster including its nodes
+func describeCacheCluster(ctx context.Context, client ElastiCacheAPI, id string) ([]elasticachetypes.CacheCluster, error) {
+	slog.Debug("Describing cache cluster", "cache_cluster_id", id)
 
-	// Log the ARNs of found resources
-	if len(output.ResourceTagMappingList) > 0 {
-		arns := make([]string, 0, len(output.ResourceTagMappingList))
-		for _, mapping := range output.ResourceTagMappingList {
-			if mapping.ResourceARN != nil {
-				arns = append(arns, *mapping.ResourceARN)
-			}
-		}
-		slog.Debug("Found resource ARNs", "arns", arns)
+	input := &elasticache.DescribeCacheClustersInput{
+		CacheClusterId:    aws.String(id),
+		ShowCacheNodeInfo: aws.Bool(true),
 	}
 
-	return output.ResourceTagMappingList, nil
-}
+	// Catch panic and convert to error
+	var resp *elasticache.DescribeCacheClustersOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during DescribeCacheClusters API call: %v", r)
+			}
+		}()
+		resp, err = client.DescribeCacheClusters(ctx, input)
+	}()
 
-// buildTagFilters converts a map of tags to AWS TagFilter array
-func buildTagFilters(tags map[string]string) []taggingtypes.TagFilter {
-	tagFilters := []taggingtypes.TagFilter{}
-	for key, value := range tags {
-		tagFilters = append(tagFilters, taggingtypes.TagFilter{
-			Key:    aws.String(key),
-			Values: []string{value},
-		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cache cluster %s: %w", id, err)
 	}
-	return tagFilters
-}
 
-// buildARNToTagsMap builds a map from ARN to tags
-func buildARNToTagsMap(resourceTagMappings []taggingtypes.ResourceTagMapping) map[string]map[string]string {
-	arnToTags := make(map[string]map[string]string)
-	for _, mapping := range resourceTagMappings {
-		arn := *mapping.ResourceARN
-		tagsMap := make(map[string]string)
-		for _, tag := range mapping.Tags {
-			if tag.Key != nil && tag.Value != nil {
-				tagsMap[*tag.Key] = *tag.Value
-			}
-		}
-		arnToTags[arn] = tagsMap
-	}
-	return arnToTags
+	return resp.CacheClusters, nil
 }
 
 // extractReplicationGroupIDsFromARNs extracts replication group IDs from ARNs
@@ -342,3 +431,48 @@ func extractNodesFromReplicationGroups(replicationGroups []elasticachetypes.Repl
 
 	return result
 }
+
+// isRedisEngine reports whether a cache cluster engine speaks the Redis protocol
+func isRedisEngine(engine string) bool {
+	return engine == "redis" || engine == "valkey"
+}
+
+// extractNodesFromStandaloneCluster extracts the nodes of a cache cluster outside a
+// replication group. Such a cluster is reported as a single shard named after its
+// node, and the node is its primary.
+func extractNodesFromStandaloneCluster(cluster elasticachetypes.CacheCluster, tags map[string]string) []providers.Resource {
+	var result []providers.Resource
+	clusterName := aws.ToString(cluster.CacheClusterId)
+
+	for _, node := range cluster.CacheNodes {
+		shardName := aws.ToString(node.CacheNodeId)
+		if node.Endpoint == nil {
+			slog.Warn("Cache node has no endpoint",
+				"cache_cluster_id", clusterName,
+				"cache_node_id", shardName)
+			continue
+		}
+
+		resource := providers.Resource{
+			Host: aws.ToString(node.Endpoint.Address),
+			Port: int(aws.ToInt32(node.Endpoint.Port)),
+			Tags: tags,
+			Metadata: map[string]interface{}{
+				"ClusterName":    clusterName,
+				"ShardName":      shardName,
+				"IsPrimary":      true,
+				"CacheClusterID": clusterName,
+			},
+		}
+
+		slog.Debug("Extracted node",
+			"host", resource.Host,
+			"port", resource.Port,
+			"is_primary", true,
+			"shard", shardName)
+
+		result = append(result, resource)
+	}
+
+	return result
+}
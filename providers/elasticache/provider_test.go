@@ -2,6 +2,7 @@ package elasticache
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -28,6 +29,22 @@ func (m *MockElastiCacheClient) DescribeReplicationGroups(ctx context.Context, p
 	return args.Get(0).(*elasticache.DescribeReplicationGroupsOutput), args.Error(1)
 }
 
+func (m *MockElastiCacheClient) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*elasticache.DescribeCacheClustersOutput), args.Error(1)
+}
+
+func (m *MockElastiCacheClient) DescribeServerlessCaches(ctx context.Context, params *elasticache.DescribeServerlessCachesInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeServerlessCachesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*elasticache.DescribeServerlessCachesOutput), args.Error(1)
+}
+
 // MockResourceGroupsTaggingClient is a mock implementation of ResourceGroupsTaggingAPI
 type MockResourceGroupsTaggingClient struct {
 	mock.Mock
@@ -41,6 +58,16 @@ func (m *MockResourceGroupsTaggingClient) GetResources(ctx context.Context, para
 	return args.Get(0).(*resourcegroupstaggingapi.GetResourcesOutput), args.Error(1)
 }
 
+// newTestProvider creates a provider whose client factory always returns the given mocks
+func newTestProvider(tagging ResourceGroupsTaggingAPI, elastiCache ElastiCacheAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			ElastiCache: elastiCache,
+			Tagging:     tagging,
+		}, nil
+	})
+}
+
 func TestProvider_Type(t *testing.T) {
 	provider := NewProvider()
 	assert.Equal(t, "elasticache_redis", provider.Type())
@@ -96,9 +123,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockElastiCache := new(MockElastiCacheClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = mockElastiCache
+		provider := newTestProvider(mockTagging, mockElastiCache)
 
 		// Setup mocks
 		taggingOutput := &resourcegroupstaggingapi.GetResourcesOutput{
@@ -136,7 +161,7 @@ func TestProvider_Discover(t *testing.T) {
 				},
 			},
 		}
-		mockElastiCache.On("DescribeReplicationGroups", ctx, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
 
 		cfg := providers.ProviderConfig{
 			Region: "ap-northeast-1",
@@ -170,9 +195,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockElastiCache := new(MockElastiCacheClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = mockElastiCache
+		provider := newTestProvider(mockTagging, mockElastiCache)
 
 		taggingOutput := &resourcegroupstaggingapi.GetResourcesOutput{
 			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{},
@@ -201,9 +224,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockElastiCache := new(MockElastiCacheClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = mockElastiCache
+		provider := newTestProvider(mockTagging, mockElastiCache)
 
 		taggingOutput := &resourcegroupstaggingapi.GetResourcesOutput{
 			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
@@ -250,7 +271,7 @@ func TestProvider_Discover(t *testing.T) {
 				},
 			},
 		}
-		mockElastiCache.On("DescribeReplicationGroups", ctx, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
 
 		cfg := providers.ProviderConfig{
 			Region: "ap-northeast-1",
@@ -279,9 +300,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockElastiCache := new(MockElastiCacheClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = mockElastiCache
+		provider := newTestProvider(mockTagging, mockElastiCache)
 
 		taggingOutput := &resourcegroupstaggingapi.GetResourcesOutput{
 			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
@@ -321,7 +340,7 @@ func TestProvider_Discover(t *testing.T) {
 				},
 			},
 		}
-		mockElastiCache.On("DescribeReplicationGroups", ctx, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(elasticacheOutput, nil)
 
 		cfg := providers.ProviderConfig{
 			Region: "ap-northeast-1",
@@ -361,9 +380,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockTagging := new(MockResourceGroupsTaggingClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = new(MockElastiCacheClient)
+		provider := newTestProvider(mockTagging, new(MockElastiCacheClient))
 
 		// Mock GetResources to return an error
 		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(nil, assert.AnError)
@@ -389,9 +406,7 @@ func TestProvider_Discover(t *testing.T) {
 		mockElastiCache := new(MockElastiCacheClient)
 		ctx := context.Background()
 
-		provider := NewProvider()
-		provider.taggingClient = mockTagging
-		provider.elasticacheClient = mockElastiCache
+		provider := newTestProvider(mockTagging, mockElastiCache)
 
 		taggingOutput := &resourcegroupstaggingapi.GetResourcesOutput{
 			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
@@ -402,7 +417,7 @@ func TestProvider_Discover(t *testing.T) {
 			},
 		}
 		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(taggingOutput, nil)
-		mockElastiCache.On("DescribeReplicationGroups", ctx, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
 
 		cfg := providers.ProviderConfig{
 			Region: "ap-northeast-1",
@@ -417,75 +432,408 @@ func TestProvider_Discover(t *testing.T) {
 	})
 }
 
-func TestExtractTagFilters(t *testing.T) {
-	t.Run("extract tags from filters", func(t *testing.T) {
-		filters := map[string]interface{}{
-			"tags": map[string]interface{}{
-				"Environment": "production",
-				"Team":        "backend",
+func TestProvider_Discover_MultipleReplicationGroups(t *testing.T) {
+	mockTagging := new(MockResourceGroupsTaggingClient)
+	mockElastiCache := new(MockElastiCacheClient)
+	ctx := context.Background()
+
+	provider := newTestProvider(mockTagging, mockElastiCache)
+
+	ids := []string{"cluster-a", "cluster-b", "cluster-c", "cluster-d", "cluster-e", "cluster-f", "cluster-g"}
+	var mappings []taggingtypes.ResourceTagMapping
+	for _, id := range ids {
+		mappings = append(mappings, taggingtypes.ResourceTagMapping{
+			ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:replicationgroup:" + id),
+			Tags:        []taggingtypes.Tag{{Key: aws.String("cluster"), Value: aws.String(id)}},
+		})
+	}
+	mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+		ResourceTagMappingList: mappings,
+	}, nil)
+
+	for _, id := range ids {
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeReplicationGroupsInput) bool {
+			return aws.ToString(input.ReplicationGroupId) == id
+		}), mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: []elasticachetypes.ReplicationGroup{
+				{
+					ReplicationGroupId: aws.String(id),
+					NodeGroups: []elasticachetypes.NodeGroup{
+						{
+							NodeGroupId: aws.String("0001"),
+							NodeGroupMembers: []elasticachetypes.NodeGroupMember{
+								{
+									CurrentRole: aws.String("primary"),
+									ReadEndpoint: &elasticachetypes.Endpoint{
+										Address: aws.String(id + ".cache.amazonaws.com"),
+										Port:    aws.Int32(6379),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, nil).Once()
+	}
+
+	result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+	require.NoError(t, err)
+	require.Len(t, result, len(ids))
+
+	// Results keep the order returned by the tagging API
+	for i, id := range ids {
+		assert.Equal(t, id+".cache.amazonaws.com", result[i].Host)
+		assert.Equal(t, id, result[i].Metadata["ClusterName"])
+		assert.Equal(t, id, result[i].Tags["cluster"])
+	}
+
+	mockTagging.AssertExpectations(t)
+	mockElastiCache.AssertExpectations(t)
+}
+
+func TestProvider_Discover_MultipleRegions(t *testing.T) {
+	ctx := context.Background()
+
+	newRegionMocks := func(region, id string) (*MockResourceGroupsTaggingClient, *MockElastiCacheClient) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{
+					ResourceARN: aws.String("arn:aws:elasticache:" + region + ":123456789012:replicationgroup:" + id),
+					Tags:        []taggingtypes.Tag{},
+				},
 			},
-		}
+		}, nil)
+
+		mockElastiCache := new(MockElastiCacheClient)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeReplicationGroupsInput) bool {
+			return aws.ToString(input.ReplicationGroupId) == id
+		}), mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: []elasticachetypes.ReplicationGroup{
+				{
+					ReplicationGroupId: aws.String(id),
+					NodeGroups: []elasticachetypes.NodeGroup{
+						{
+							NodeGroupId: aws.String("0001"),
+							NodeGroupMembers: []elasticachetypes.NodeGroupMember{
+								{
+									CurrentRole: aws.String("primary"),
+									ReadEndpoint: &elasticachetypes.Endpoint{
+										Address: aws.String(id + "." + region + ".cache.amazonaws.com"),
+										Port:    aws.Int32(6379),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}, nil)
+
+		return mockTagging, mockElastiCache
+	}
+
+	tokyoTagging, tokyoElastiCache := newRegionMocks("ap-northeast-1", "tokyo-cluster")
+	virginiaTagging, virginiaElastiCache := newRegionMocks("us-east-1", "virginia-cluster")
 
-		result := extractTagFilters(filters)
-		assert.Len(t, result, 2)
-		assert.Equal(t, "production", result["Environment"])
-		assert.Equal(t, "backend", result["Team"])
+	clientsByRegion := map[string]*Clients{
+		"ap-northeast-1": {Tagging: tokyoTagging, ElastiCache: tokyoElastiCache},
+		"us-east-1":      {Tagging: virginiaTagging, ElastiCache: virginiaElastiCache},
+	}
+	provider := NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		clients, ok := clientsByRegion[cfg.Region]
+		require.True(t, ok, "unexpected region %s", cfg.Region)
+		return clients, nil
 	})
 
-	t.Run("no tags in filters", func(t *testing.T) {
-		filters := map[string]interface{}{
-			"other": "value",
+	tokyo, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+	require.NoError(t, err)
+	require.Len(t, tokyo, 1)
+	assert.Equal(t, "tokyo-cluster.ap-northeast-1.cache.amazonaws.com", tokyo[0].Host)
+
+	virginia, err := provider.Discover(ctx, providers.ProviderConfig{Region: "us-east-1"})
+	require.NoError(t, err)
+	require.Len(t, virginia, 1)
+	assert.Equal(t, "virginia-cluster.us-east-1.cache.amazonaws.com", virginia[0].Host)
+
+	// Each region only used its own clients
+	tokyoTagging.AssertNumberOfCalls(t, "GetResources", 1)
+	tokyoElastiCache.AssertNumberOfCalls(t, "DescribeReplicationGroups", 1)
+	virginiaTagging.AssertNumberOfCalls(t, "GetResources", 1)
+	virginiaElastiCache.AssertNumberOfCalls(t, "DescribeReplicationGroups", 1)
+}
+
+func TestProvider_Discover_ClientFactoryError(t *testing.T) {
+	provider := NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return nil, assert.AnError
+	})
+
+	_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+// newReplicationGroup returns a replication group with a single primary node
+func newReplicationGroup(id string) elasticachetypes.ReplicationGroup {
+	return elasticachetypes.ReplicationGroup{
+		ReplicationGroupId: aws.String(id),
+		NodeGroups: []elasticachetypes.NodeGroup{
+			{
+				NodeGroupId: aws.String("0001"),
+				NodeGroupMembers: []elasticachetypes.NodeGroupMember{
+					{
+						CurrentRole: aws.String("primary"),
+						ReadEndpoint: &elasticachetypes.Endpoint{
+							Address: aws.String(id + ".cache.amazonaws.com"),
+							Port:    aws.Int32(6379),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newReplicationGroupMapping returns a tag mapping for a replication group ID
+func newReplicationGroupMapping(id string) taggingtypes.ResourceTagMapping {
+	return taggingtypes.ResourceTagMapping{
+		ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:replicationgroup:" + id),
+		Tags:        []taggingtypes.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+	}
+}
+
+func TestProvider_Discover_Pagination(t *testing.T) {
+	t.Run("tagging API pages are followed", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+			return input.PaginationToken == nil
+		}), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newReplicationGroupMapping("page1-cluster")},
+			PaginationToken:        aws.String("token-2"),
+		}, nil).Once()
+		mockTagging.On("GetResources", ctx, mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+			return aws.ToString(input.PaginationToken) == "token-2"
+		}), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newReplicationGroupMapping("page2-cluster")},
+			PaginationToken:        aws.String(""),
+		}, nil).Once()
+
+		for _, id := range []string{"page1-cluster", "page2-cluster"} {
+			mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeReplicationGroupsInput) bool {
+				return aws.ToString(input.ReplicationGroupId) == id
+			}), mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+				ReplicationGroups: []elasticachetypes.ReplicationGroup{newReplicationGroup(id)},
+			}, nil).Once()
 		}
 
-		result := extractTagFilters(filters)
-		assert.Len(t, result, 0)
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "page1-cluster.cache.amazonaws.com", result[0].Host)
+		assert.Equal(t, "page2-cluster.cache.amazonaws.com", result[1].Host)
+
+		mockTagging.AssertExpectations(t)
+		mockElastiCache.AssertExpectations(t)
 	})
 
-	t.Run("nil filters", func(t *testing.T) {
-		result := extractTagFilters(nil)
-		assert.Len(t, result, 0)
+	t.Run("many groups are listed in pages and joined", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockTagging, mockElastiCache)
+
+		// Tagged groups, in the order returned by the tagging API
+		var taggedIDs []string
+		var mappings []taggingtypes.ResourceTagMapping
+		for i := batchDescribeThreshold + 5; i > 0; i-- {
+			id := fmt.Sprintf("cluster-%02d", i)
+			taggedIDs = append(taggedIDs, id)
+			mappings = append(mappings, newReplicationGroupMapping(id))
+		}
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: mappings,
+		}, nil)
+
+		// All groups in the region, split across two pages and including an untagged group
+		var page1, page2 []elasticachetypes.ReplicationGroup
+		for i, id := range taggedIDs {
+			if i%2 == 0 {
+				page1 = append(page1, newReplicationGroup(id))
+			} else {
+				page2 = append(page2, newReplicationGroup(id))
+			}
+		}
+		page2 = append(page2, newReplicationGroup("untagged-cluster"))
+
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeReplicationGroupsInput) bool {
+			return input.ReplicationGroupId == nil && input.Marker == nil
+		}), mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: page1,
+			Marker:            aws.String("marker-2"),
+		}, nil).Once()
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeReplicationGroupsInput) bool {
+			return input.ReplicationGroupId == nil && aws.ToString(input.Marker) == "marker-2"
+		}), mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: page2,
+		}, nil).Once()
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		require.Len(t, result, len(taggedIDs))
+
+		for i, id := range taggedIDs {
+			assert.Equal(t, id+".cache.amazonaws.com", result[i].Host)
+			assert.Equal(t, id, result[i].Metadata["ClusterName"])
+			assert.Equal(t, "prod", result[i].Tags["env"])
+		}
+
+		mockTagging.AssertExpectations(t)
+		mockElastiCache.AssertExpectations(t)
 	})
 
-	t.Run("non-string tag values", func(t *testing.T) {
-		filters := map[string]interface{}{
-			"tags": map[string]interface{}{
-				"String": "value",
-				"Number": 123,
-				"Bool":   true,
-			},
+	t.Run("listing error", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockTagging, mockElastiCache)
+
+		var mappings []taggingtypes.ResourceTagMapping
+		for i := 0; i <= batchDescribeThreshold; i++ {
+			mappings = append(mappings, newReplicationGroupMapping(fmt.Sprintf("cluster-%02d", i)))
 		}
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: mappings,
+		}, nil)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
 
-		result := extractTagFilters(filters)
-		assert.Len(t, result, 1)
-		assert.Equal(t, "value", result["String"])
+		_, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to list replication groups")
 	})
 }
 
-func TestBuildTagFilters(t *testing.T) {
-	t.Run("build tag filters", func(t *testing.T) {
-		tags := map[string]string{
-			"Environment": "production",
-			"Team":        "backend",
-		}
+// resourceTypeFilter matches GetResources calls for a single resource type
+func resourceTypeFilter(resourceType string) interface{} {
+	return mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+		return len(input.ResourceTypeFilters) == 1 && input.ResourceTypeFilters[0] == resourceType
+	})
+}
 
-		tagFilters := buildTagFilters(tags)
-		assert.Len(t, tagFilters, 2)
+// newCacheCluster returns a cache cluster with a single node
+func newCacheCluster(id, engine string, replicationGroupID *string) elasticachetypes.CacheCluster {
+	return elasticachetypes.CacheCluster{
+		CacheClusterId:     aws.String(id),
+		Engine:             aws.String(engine),
+		ReplicationGroupId: replicationGroupID,
+		CacheNodes: []elasticachetypes.CacheNode{
+			{
+				CacheNodeId: aws.String("0001"),
+				Endpoint: &elasticachetypes.Endpoint{
+					Address: aws.String(id + ".cache.amazonaws.com"),
+					Port:    aws.Int32(6379),
+				},
+			},
+		},
+	}
+}
+
+func TestProvider_Discover_Standalone(t *testing.T) {
+	cfg := providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"tags":               map[string]interface{}{"env": "prod"},
+			"include_standalone": true,
+		},
+	}
+
+	t.Run("standalone clusters are appended after replication groups", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
 
-		tagMap := make(map[string]string)
-		for _, filter := range tagFilters {
-			require.NotNil(t, filter.Key)
-			require.Len(t, filter.Values, 1)
-			tagMap[*filter.Key] = filter.Values[0]
+		provider := newTestProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("elasticache:replicationgroup"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newReplicationGroupMapping("group")},
+		}, nil)
+		mockElastiCache.On("DescribeReplicationGroups", mock.Anything, mock.Anything, mock.Anything).Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: []elasticachetypes.ReplicationGroup{newReplicationGroup("group")},
+		}, nil)
+
+		clusterIDs := []string{"single-redis", "single-valkey", "group-001", "memcached"}
+		mappings := make([]taggingtypes.ResourceTagMapping, 0, len(clusterIDs))
+		for _, id := range clusterIDs {
+			mappings = append(mappings, taggingtypes.ResourceTagMapping{
+				ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:cluster:" + id),
+				Tags:        []taggingtypes.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+			})
 		}
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("elasticache:cluster"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: mappings,
+		}, nil)
+
+		clusters := map[string]elasticachetypes.CacheCluster{
+			"single-redis":  newCacheCluster("single-redis", "redis", nil),
+			"single-valkey": newCacheCluster("single-valkey", "valkey", nil),
+			"group-001":     newCacheCluster("group-001", "redis", aws.String("group")),
+			"memcached":     newCacheCluster("memcached", "memcached", nil),
+		}
+		for id, cluster := range clusters {
+			mockElastiCache.On("DescribeCacheClusters", mock.Anything, mock.MatchedBy(func(input *elasticache.DescribeCacheClustersInput) bool {
+				return aws.ToString(input.CacheClusterId) == id && aws.ToBool(input.ShowCacheNodeInfo)
+			}), mock.Anything).Return(&elasticache.DescribeCacheClustersOutput{
+				CacheClusters: []elasticachetypes.CacheCluster{cluster},
+			}, nil)
+		}
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		require.Len(t, result, 3)
 
-		assert.Equal(t, "production", tagMap["Environment"])
-		assert.Equal(t, "backend", tagMap["Team"])
+		assert.Equal(t, "group.cache.amazonaws.com", result[0].Host)
+
+		assert.Equal(t, "single-redis.cache.amazonaws.com", result[1].Host)
+		assert.Equal(t, 6379, result[1].Port)
+		assert.Equal(t, "prod", result[1].Tags["env"])
+		assert.Equal(t, "single-redis", result[1].Metadata["ClusterName"])
+		assert.Equal(t, "0001", result[1].Metadata["ShardName"])
+		assert.Equal(t, true, result[1].Metadata["IsPrimary"])
+		assert.Equal(t, "single-redis", result[1].Metadata["CacheClusterID"])
+
+		assert.Equal(t, "single-valkey.cache.amazonaws.com", result[2].Host)
+
+		mockTagging.AssertExpectations(t)
+		mockElastiCache.AssertExpectations(t)
 	})
 
-	t.Run("empty tags", func(t *testing.T) {
-		tags := map[string]string{}
-		tagFilters := buildTagFilters(tags)
-		assert.Len(t, tagFilters, 0)
+	t.Run("standalone clusters are not queried by default", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockTagging, new(MockElastiCacheClient))
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("elasticache:replicationgroup"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockTagging.AssertNumberOfCalls(t, "GetResources", 1)
+	})
+
+	t.Run("invalid include_standalone", func(t *testing.T) {
+		provider := NewProvider()
+		err := provider.ValidateConfig(providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"include_standalone": "yes"},
+		})
+		assert.EqualError(t, err, "filters.include_standalone must be a boolean")
 	})
 }
 
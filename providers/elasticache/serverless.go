@@ -0,0 +1,197 @@
+package elasticache
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const serverlessProviderType = "elasticache_serverless"
+
+// serverlessCacheResourceType is the Resource Groups Tagging API type of serverless caches
+const serverlessCacheResourceType = "elasticache:serverlesscache"
+
+// Endpoint types reported in the EndpointType metadata of serverless caches
+const (
+	serverlessEndpointPrimary = "primary"
+	serverlessEndpointReader  = "reader"
+)
+
+// ServerlessProvider implements the providers.Provider interface for ElastiCache Serverless
+type ServerlessProvider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// NewServerlessProvider creates a new ElastiCache Serverless provider
+func NewServerlessProvider() *ServerlessProvider {
+	return NewServerlessProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewServerlessProviderWithClientFactory creates a new ElastiCache Serverless provider
+// that obtains its AWS clients from the given factory
+func NewServerlessProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *ServerlessProvider {
+	return &ServerlessProvider{
+		newClients: newClients,
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *ServerlessProvider) Type() string {
+	return serverlessProviderType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *ServerlessProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves the endpoints of ElastiCache Serverless caches based on the configuration
+func (p *ServerlessProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting ElastiCache Serverless discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	// Get serverless caches by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, serverlessCacheResourceType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourceTagMappings) == 0 {
+		slog.Info("No serverless caches found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found serverless caches by tags", "count", len(resourceTagMappings))
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	arns := make([]string, 0, len(resourceTagMappings))
+	for _, mapping := range resourceTagMappings {
+		arns = append(arns, aws.ToString(mapping.ResourceARN))
+	}
+
+	// Describe serverless caches in parallel; results keep the order of the ARNs
+	cachesPerARN, err := providers.ParallelMap(ctx, arns, describeConcurrency, func(ctx context.Context, arn string) ([]elasticachetypes.ServerlessCache, error) {
+		return describeServerlessCache(ctx, clients.ElastiCache, awsutil.ResourceIDFromARN(arn))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for i, caches := range cachesPerARN {
+		for _, cache := range caches {
+			endpoints := extractEndpointsFromServerlessCache(cache, arnToTags[arns[i]])
+			slog.Debug("Extracted endpoints from serverless cache",
+				"serverless_cache_name", aws.ToString(cache.ServerlessCacheName),
+				"endpoints_count", len(endpoints))
+			result = append(result, endpoints...)
+		}
+	}
+
+	slog.Info("ElastiCache Serverless discovery completed", "total_endpoints", len(result))
+	return result, nil
+}
+
+// describeServerlessCache describes a single serverless cache
+func describeServerlessCache(ctx context.Context, client ElastiCacheAPI, name string) ([]elasticachetypes.ServerlessCache, error) {
+	slog.Debug("Describing serverless cache", "serverless_cache_name", name)
+
+	input := &elasticache.DescribeServerlessCachesInput{
+		ServerlessCacheName: aws.String(name),
+	}
+
+	// Catch panic and convert to error
+	var resp *elasticache.DescribeServerlessCachesOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during DescribeServerlessCaches API call: %v", r)
+			}
+		}()
+		resp, err = client.DescribeServerlessCaches(ctx, input)
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe serverless cache %s: %w", name, err)
+	}
+
+	return resp.ServerlessCaches, nil
+}
+
+// extractEndpointsFromServerlessCache returns a resource for the endpoint and,
+// when the engine provides one, the reader endpoint of a serverless cache
+func extractEndpointsFromServerlessCache(cache elasticachetypes.ServerlessCache, tags map[string]string) []providers.Resource {
+	var result []providers.Resource
+	name := aws.ToString(cache.ServerlessCacheName)
+
+	if cache.Endpoint == nil {
+		slog.Warn("Serverless cache has no endpoint",
+			"serverless_cache_name", name,
+			"status", aws.ToString(cache.Status))
+		return result
+	}
+
+	endpoints := []struct {
+		endpointType string
+		endpoint     *elasticachetypes.Endpoint
+	}{
+		{serverlessEndpointPrimary, cache.Endpoint},
+		{serverlessEndpointReader, cache.ReaderEndpoint},
+	}
+
+	for _, e := range endpoints {
+		if e.endpoint == nil {
+			continue
+		}
+
+		resource := providers.Resource{
+			Host: aws.ToString(e.endpoint.Address),
+			Port: int(aws.ToInt32(e.endpoint.Port)),
+			Tags: tags,
+			Metadata: map[string]interface{}{
+				"ServerlessCacheName": name,
+				"EndpointType":        e.endpointType,
+				"IsPrimary":           e.endpointType == serverlessEndpointPrimary,
+				"Engine":              aws.ToString(cache.Engine),
+				"MajorEngineVersion":  aws.ToString(cache.MajorEngineVersion),
+				"FullEngineVersion":   aws.ToString(cache.FullEngineVersion),
+				"Status":              aws.ToString(cache.Status),
+				// Serverless caches always require TLS
+				"TLSEnabled":       true,
+				"SecurityGroupIDs": cache.SecurityGroupIds,
+				"KmsKeyID":         aws.ToString(cache.KmsKeyId),
+				"UserGroupID":      aws.ToString(cache.UserGroupId),
+			},
+		}
+
+		slog.Debug("Extracted endpoint",
+			"host", resource.Host,
+			"port", resource.Port,
+			"serverless_cache_name", name,
+			"endpoint_type", e.endpointType)
+
+		result = append(result, resource)
+	}
+
+	return result
+}
@@ -0,0 +1,195 @@
+package elasticache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServerlessProvider creates a Serverless provider whose client factory always returns the given mocks
+func newTestServerlessProvider(tagging ResourceGroupsTaggingAPI, elastiCache ElastiCacheAPI) *ServerlessProvider {
+	return NewServerlessProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			ElastiCache: elastiCache,
+			Tagging:     tagging,
+		}, nil
+	})
+}
+
+func TestServerlessProvider_Type(t *testing.T) {
+	provider := NewServerlessProvider()
+	assert.Equal(t, "elasticache_serverless", provider.Type())
+}
+
+func TestServerlessProvider_Discover(t *testing.T) {
+	cfg := providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Environment": "production",
+			},
+		},
+	}
+
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestServerlessProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("elasticache:serverlesscache"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{
+					ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:serverlesscache:my-valkey"),
+					Tags: []taggingtypes.Tag{
+						{Key: aws.String("Environment"), Value: aws.String("production")},
+					},
+				},
+				{
+					ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:serverlesscache:my-memcached"),
+				},
+			},
+		}, nil)
+
+		mockElastiCache.On("DescribeServerlessCaches", mock.Anything, &elasticache.DescribeServerlessCachesInput{
+			ServerlessCacheName: aws.String("my-valkey"),
+		}, mock.Anything).Return(&elasticache.DescribeServerlessCachesOutput{
+			ServerlessCaches: []elasticachetypes.ServerlessCache{
+				{
+					ServerlessCacheName: aws.String("my-valkey"),
+					Engine:              aws.String("valkey"),
+					MajorEngineVersion:  aws.String("8"),
+					FullEngineVersion:   aws.String("8.0"),
+					Status:              aws.String("available"),
+					SecurityGroupIds:    []string{"sg-12345678"},
+					KmsKeyId:            aws.String("arn:aws:kms:ap-northeast-1:123456789012:key/abc"),
+					UserGroupId:         aws.String("my-user-group"),
+					Endpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("my-valkey-abc123.serverless.apne1.cache.amazonaws.com"),
+						Port:    aws.Int32(6379),
+					},
+					ReaderEndpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("my-valkey-abc123.serverless.apne1.cache.amazonaws.com"),
+						Port:    aws.Int32(6380),
+					},
+				},
+			},
+		}, nil)
+
+		mockElastiCache.On("DescribeServerlessCaches", mock.Anything, &elasticache.DescribeServerlessCachesInput{
+			ServerlessCacheName: aws.String("my-memcached"),
+		}, mock.Anything).Return(&elasticache.DescribeServerlessCachesOutput{
+			ServerlessCaches: []elasticachetypes.ServerlessCache{
+				{
+					ServerlessCacheName: aws.String("my-memcached"),
+					Engine:              aws.String("memcached"),
+					Endpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("my-memcached-abc123.serverless.apne1.cache.amazonaws.com"),
+						Port:    aws.Int32(11211),
+					},
+				},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		primary := result[0]
+		assert.Equal(t, "my-valkey-abc123.serverless.apne1.cache.amazonaws.com", primary.Host)
+		assert.Equal(t, 6379, primary.Port)
+		assert.Equal(t, "production", primary.Tags["Environment"])
+		assert.Equal(t, "my-valkey", primary.Metadata["ServerlessCacheName"])
+		assert.Equal(t, "primary", primary.Metadata["EndpointType"])
+		assert.Equal(t, true, primary.Metadata["IsPrimary"])
+		assert.Equal(t, "valkey", primary.Metadata["Engine"])
+		assert.Equal(t, "8", primary.Metadata["MajorEngineVersion"])
+		assert.Equal(t, "8.0", primary.Metadata["FullEngineVersion"])
+		assert.Equal(t, "available", primary.Metadata["Status"])
+		assert.Equal(t, true, primary.Metadata["TLSEnabled"])
+		assert.Equal(t, []string{"sg-12345678"}, primary.Metadata["SecurityGroupIDs"])
+		assert.Equal(t, "arn:aws:kms:ap-northeast-1:123456789012:key/abc", primary.Metadata["KmsKeyID"])
+		assert.Equal(t, "my-user-group", primary.Metadata["UserGroupID"])
+
+		reader := result[1]
+		assert.Equal(t, 6380, reader.Port)
+		assert.Equal(t, "reader", reader.Metadata["EndpointType"])
+		assert.Equal(t, false, reader.Metadata["IsPrimary"])
+
+		// Memcached has no reader endpoint
+		assert.Equal(t, "my-memcached-abc123.serverless.apne1.cache.amazonaws.com", result[2].Host)
+		assert.Equal(t, "primary", result[2].Metadata["EndpointType"])
+
+		mockTagging.AssertExpectations(t)
+		mockElastiCache.AssertExpectations(t)
+	})
+
+	t.Run("cache without endpoint is skipped", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestServerlessProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:serverlesscache:creating")},
+			},
+		}, nil)
+		mockElastiCache.On("DescribeServerlessCaches", mock.Anything, mock.Anything, mock.Anything).Return(&elasticache.DescribeServerlessCachesOutput{
+			ServerlessCaches: []elasticachetypes.ServerlessCache{
+				{ServerlessCacheName: aws.String("creating"), Status: aws.String("creating")},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestServerlessProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockElastiCache.AssertNotCalled(t, "DescribeServerlessCaches", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockElastiCache := new(MockElastiCacheClient)
+		ctx := context.Background()
+
+		provider := newTestServerlessProvider(mockTagging, mockElastiCache)
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				{ResourceARN: aws.String("arn:aws:elasticache:ap-northeast-1:123456789012:serverlesscache:my-valkey")},
+			},
+		}, nil)
+		mockElastiCache.On("DescribeServerlessCaches", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("API error"))
+
+		result, err := provider.Discover(ctx, cfg)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "failed to describe serverless cache my-valkey")
+	})
+}
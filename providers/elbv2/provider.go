@@ -0,0 +1,472 @@
+package elbv2
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "elbv2_listener"
+
+// loadBalancerResourceType is the tagging API resource type of Classic and ELBv2 load balancers
+const loadBalancerResourceType = "elasticloadbalancing:loadbalancer"
+
+const (
+	// protocolsFilter restricts discovery to listeners using the listed protocols
+	protocolsFilter = "protocols"
+	// includeTargetHealthFilter enables DescribeTargetHealth for the listener target groups
+	includeTargetHealthFilter = "include_target_health"
+)
+
+const (
+	// describeConcurrency is the maximum number of describe calls in flight
+	describeConcurrency = 5
+	// describeBatchSize is the maximum number of ARNs accepted by a DescribeLoadBalancers call
+	describeBatchSize = 20
+)
+
+// Provider implements the providers.Provider interface for ELBv2 load balancer listeners
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	ELBv2   ELBv2API
+	Tagging awsutil.ResourceGroupsTaggingAPI
+}
+
+// ELBv2API defines the Elastic Load Balancing v2 API interface
+type ELBv2API interface {
+	DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error)
+	DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error)
+	DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error)
+}
+
+// NewProvider creates a new ELBv2 provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new ELBv2 provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		ELBv2:   elasticloadbalancingv2.NewFromConfig(awsCfg),
+		Tagging: resourcegroupstaggingapi.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	if _, err := providers.StringListFilter(cfg.Filters, protocolsFilter); err != nil {
+		return err
+	}
+
+	_, err := providers.BoolFilter(cfg.Filters, includeTargetHealthFilter)
+	return err
+}
+
+// Discover retrieves ELBv2 listeners based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting ELBv2 discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	protocols, _ := providers.StringListFilter(cfg.Filters, protocolsFilter)
+	includeTargetHealth, _ := providers.BoolFilter(cfg.Filters, includeTargetHealthFilter)
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags,
+		"protocols", protocols, "include_target_health", includeTargetHealth)
+
+	// Get load balancers by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, loadBalancerResourceType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	// The resource type also matches Classic Load Balancers, which the ELBv2 API rejects
+	var arns []string
+	for _, mapping := range resourceTagMappings {
+		arn := aws.ToString(mapping.ResourceARN)
+		if !isELBv2ARN(arn) {
+			slog.Debug("Skipping Classic Load Balancer", "arn", arn)
+			continue
+		}
+		arns = append(arns, arn)
+	}
+
+	if len(arns) == 0 {
+		slog.Info("No load balancers found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found load balancers by tags", "count", len(arns))
+
+	// Describe load balancers; results keep the order of the ARNs
+	loadBalancers, err := describeLoadBalancers(ctx, clients.ELBv2, arns)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []elbv2types.LoadBalancer
+	for _, lb := range loadBalancers {
+		if lb.State != nil {
+			switch lb.State.Code {
+			case elbv2types.LoadBalancerStateEnumProvisioning, elbv2types.LoadBalancerStateEnumFailed:
+				slog.Debug("Skipping load balancer",
+					"load_balancer_name", aws.ToString(lb.LoadBalancerName),
+					"state", lb.State.Code)
+				continue
+			}
+		}
+		active = append(active, lb)
+	}
+
+	// Get listeners in parallel; results keep the order of the load balancers
+	listenersPerLB, err := providers.ParallelMap(ctx, active, describeConcurrency, func(ctx context.Context, lb elbv2types.LoadBalancer) ([]elbv2types.Listener, error) {
+		return describeListeners(ctx, clients.ELBv2, aws.ToString(lb.LoadBalancerArn))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, listeners := range listenersPerLB {
+		listenersPerLB[i] = filterListeners(listeners, protocols)
+	}
+
+	var targetHealth map[string][]elbv2types.TargetHealthDescription
+	if includeTargetHealth {
+		targetHealth, err = describeTargetHealth(ctx, clients.ELBv2, listenersPerLB)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var result []providers.Resource
+	for i, lb := range active {
+		for _, listener := range listenersPerLB[i] {
+			resource := newListenerResource(lb, listener, arnToTags[aws.ToString(lb.LoadBalancerArn)], includeTargetHealth, targetHealth)
+			slog.Debug("Extracted listener",
+				"host", resource.Host,
+				"port", resource.Port,
+				"load_balancer_name", aws.ToString(lb.LoadBalancerName),
+				"protocol", listener.Protocol)
+			result = append(result, resource)
+		}
+	}
+
+	slog.Info("ELBv2 discovery completed", "total_listeners", len(result))
+	return result, nil
+}
+
+// isELBv2ARN reports whether arn is the ARN of an Application, Network or Gateway Load Balancer
+func isELBv2ARN(arn string) bool {
+	for _, prefix := range []string{":loadbalancer/app/", ":loadbalancer/net/", ":loadbalancer/gwy/"} {
+		if strings.Contains(arn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeLoadBalancers returns the load balancers with the given ARNs, in the order of arns.
+// ARNs are looked up in batches of describeBatchSize.
+func describeLoadBalancers(ctx context.Context, client ELBv2API, arns []string) ([]elbv2types.LoadBalancer, error) {
+	var batches [][]string
+	for start := 0; start < len(arns); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(arns))
+		batches = append(batches, arns[start:end])
+	}
+
+	lbsPerBatch, err := providers.ParallelMap(ctx, batches, describeConcurrency, func(ctx context.Context, batch []string) ([]elbv2types.LoadBalancer, error) {
+		// Catch panic and convert to error
+		var resp *elasticloadbalancingv2.DescribeLoadBalancersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeLoadBalancers API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+				LoadBalancerArns: batch,
+			})
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+		return resp.LoadBalancers, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byARN := make(map[string]elbv2types.LoadBalancer, len(arns))
+	for _, lbs := range lbsPerBatch {
+		for _, lb := range lbs {
+			byARN[aws.ToString(lb.LoadBalancerArn)] = lb
+		}
+	}
+
+	var result []elbv2types.LoadBalancer
+	for _, arn := range arns {
+		if lb, ok := byARN[arn]; ok {
+			result = append(result, lb)
+		}
+	}
+	return result, nil
+}
+
+// describeListeners returns the listeners of a load balancer ordered by port, following Marker
+func describeListeners(ctx context.Context, client ELBv2API, loadBalancerARN string) ([]elbv2types.Listener, error) {
+	var result []elbv2types.Listener
+	var marker *string
+
+	for {
+		input := &elasticloadbalancingv2.DescribeListenersInput{
+			LoadBalancerArn: aws.String(loadBalancerARN),
+			Marker:          marker,
+		}
+
+		// Catch panic and convert to error
+		var resp *elasticloadbalancingv2.DescribeListenersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeListeners API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeListeners(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe listeners of %s: %w", loadBalancerARN, err)
+		}
+		result = append(result, resp.Listeners...)
+
+		if aws.ToString(resp.NextMarker) == "" {
+			break
+		}
+		marker = resp.NextMarker
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return aws.ToInt32(result[i].Port) < aws.ToInt32(result[j].Port)
+	})
+	return result, nil
+}
+
+// filterListeners returns the listeners whose protocol is in protocols, or all
+// listeners when protocols is empty. Protocols are compared case-insensitively.
+func filterListeners(listeners []elbv2types.Listener, protocols []string) []elbv2types.Listener {
+	if len(protocols) == 0 {
+		return listeners
+	}
+
+	var result []elbv2types.Listener
+	for _, listener := range listeners {
+		for _, protocol := range protocols {
+			if strings.EqualFold(string(listener.Protocol), protocol) {
+				result = append(result, listener)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// targetGroupARNs returns the target groups the default actions of a listener forward to
+func targetGroupARNs(listener elbv2types.Listener) []string {
+	var result []string
+	seen := make(map[string]bool)
+	add := func(arn string) {
+		if arn != "" && !seen[arn] {
+			seen[arn] = true
+			result = append(result, arn)
+		}
+	}
+
+	for _, action := range listener.DefaultActions {
+		if action.Type != elbv2types.ActionTypeEnumForward {
+			continue
+		}
+		add(aws.ToString(action.TargetGroupArn))
+		if action.ForwardConfig != nil {
+			for _, tg := range action.ForwardConfig.TargetGroups {
+				add(aws.ToString(tg.TargetGroupArn))
+			}
+		}
+	}
+	return result
+}
+
+// describeTargetHealth returns the health of the targets of every target group
+// used by the listeners, keyed by target group ARN
+func describeTargetHealth(ctx context.Context, client ELBv2API, listenersPerLB [][]elbv2types.Listener) (map[string][]elbv2types.TargetHealthDescription, error) {
+	var arns []string
+	seen := make(map[string]bool)
+	for _, listeners := range listenersPerLB {
+		for _, listener := range listeners {
+			for _, arn := range targetGroupARNs(listener) {
+				if !seen[arn] {
+					seen[arn] = true
+					arns = append(arns, arn)
+				}
+			}
+		}
+	}
+
+	healthPerTargetGroup, err := providers.ParallelMap(ctx, arns, describeConcurrency, func(ctx context.Context, arn string) ([]elbv2types.TargetHealthDescription, error) {
+		// Catch panic and convert to error
+		var resp *elasticloadbalancingv2.DescribeTargetHealthOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeTargetHealth API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+				TargetGroupArn: aws.String(arn),
+			})
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe target health of %s: %w", arn, err)
+		}
+		return resp.TargetHealthDescriptions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]elbv2types.TargetHealthDescription, len(arns))
+	for i, arn := range arns {
+		result[arn] = healthPerTargetGroup[i]
+	}
+	return result, nil
+}
+
+// newListenerResource returns the resource for a listener. Target health metadata is
+// only set when includeTargetHealth is true.
+func newListenerResource(lb elbv2types.LoadBalancer, listener elbv2types.Listener, tags map[string]string, includeTargetHealth bool, targetHealth map[string][]elbv2types.TargetHealthDescription) providers.Resource {
+	dnsName := aws.ToString(lb.DNSName)
+	port := int(aws.ToInt32(listener.Port))
+
+	// URL is only meaningful for HTTP listeners
+	var url string
+	switch listener.Protocol {
+	case elbv2types.ProtocolEnumHttp, elbv2types.ProtocolEnumHttps:
+		url = fmt.Sprintf("%s://%s:%d", strings.ToLower(string(listener.Protocol)), dnsName, port)
+	}
+
+	tgARNs := targetGroupARNs(listener)
+
+	metadata := map[string]interface{}{
+		"LoadBalancerName": aws.ToString(lb.LoadBalancerName),
+		"LoadBalancerArn":  aws.ToString(lb.LoadBalancerArn),
+		"LoadBalancerType": string(lb.Type),
+		"DNSName":          dnsName,
+		"Scheme":           string(lb.Scheme),
+		"VpcID":            aws.ToString(lb.VpcId),
+		"ListenerArn":      aws.ToString(listener.ListenerArn),
+		"Protocol":         string(listener.Protocol),
+		"URL":              url,
+		"TargetGroupArns":  tgARNs,
+	}
+
+	if includeTargetHealth {
+		targets := []map[string]interface{}{}
+		healthy, unhealthy := 0, 0
+		for _, arn := range tgARNs {
+			for _, desc := range targetHealth[arn] {
+				var id, az, reason, state string
+				var targetPort int
+				if desc.Target != nil {
+					id = aws.ToString(desc.Target.Id)
+					az = aws.ToString(desc.Target.AvailabilityZone)
+					targetPort = int(aws.ToInt32(desc.Target.Port))
+				}
+				if desc.TargetHealth != nil {
+					state = string(desc.TargetHealth.State)
+					reason = string(desc.TargetHealth.Reason)
+				}
+				// Other states such as initial, draining and unused are not counted
+				switch state {
+				case string(elbv2types.TargetHealthStateEnumHealthy):
+					healthy++
+				case string(elbv2types.TargetHealthStateEnumUnhealthy):
+					unhealthy++
+				}
+
+				targets = append(targets, map[string]interface{}{
+					"TargetGroupArn":   arn,
+					"ID":               id,
+					"Port":             targetPort,
+					"AvailabilityZone": az,
+					"State":            state,
+					"Reason":           reason,
+				})
+			}
+		}
+		// DescribeTargetHealth returns targets in no particular order
+		sort.SliceStable(targets, func(i, j int) bool {
+			a, b := targets[i], targets[j]
+			if a["TargetGroupArn"] != b["TargetGroupArn"] {
+				return a["TargetGroupArn"].(string) < b["TargetGroupArn"].(string)
+			}
+			if a["ID"] != b["ID"] {
+				return a["ID"].(string) < b["ID"].(string)
+			}
+			return a["Port"].(int) < b["Port"].(int)
+		})
+		metadata["Targets"] = targets
+		metadata["HealthyTargetCount"] = healthy
+		metadata["UnhealthyTargetCount"] = unhealthy
+	}
+
+	return providers.Resource{
+		Host:     dnsName,
+		Port:     port,
+		Tags:     tags,
+		Metadata: metadata,
+	}
+}
@@ -0,0 +1,382 @@
+package elbv2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	elbv2types "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockELBv2Client is a mock implementation of ELBv2API
+type MockELBv2Client struct {
+	mock.Mock
+}
+
+func (m *MockELBv2Client) DescribeLoadBalancers(ctx context.Context, params *elasticloadbalancingv2.DescribeLoadBalancersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeLoadBalancersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*elasticloadbalancingv2.DescribeLoadBalancersOutput), args.Error(1)
+}
+
+func (m *MockELBv2Client) DescribeListeners(ctx context.Context, params *elasticloadbalancingv2.DescribeListenersInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeListenersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*elasticloadbalancingv2.DescribeListenersOutput), args.Error(1)
+}
+
+func (m *MockELBv2Client) DescribeTargetHealth(ctx context.Context, params *elasticloadbalancingv2.DescribeTargetHealthInput, optFns ...func(*elasticloadbalancingv2.Options)) (*elasticloadbalancingv2.DescribeTargetHealthOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*elasticloadbalancingv2.DescribeTargetHealthOutput), args.Error(1)
+}
+
+// MockResourceGroupsTaggingClient is a mock implementation of awsutil.ResourceGroupsTaggingAPI
+type MockResourceGroupsTaggingClient struct {
+	mock.Mock
+}
+
+func (m *MockResourceGroupsTaggingClient) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*resourcegroupstaggingapi.GetResourcesOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mocks
+func newTestProvider(tagging *MockResourceGroupsTaggingClient, elbv2Client *MockELBv2Client) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			ELBv2:   elbv2Client,
+			Tagging: tagging,
+		}, nil
+	})
+}
+
+// loadBalancerARN returns the ARN of an Application Load Balancer
+func loadBalancerARN(name string) string {
+	return "arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/app/" + name + "/0123456789abcdef"
+}
+
+// targetGroupARN returns the ARN of a target group
+func targetGroupARN(name string) string {
+	return "arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:targetgroup/" + name + "/0123456789abcdef"
+}
+
+// newLoadBalancerMapping returns a tag mapping for a load balancer ARN
+func newLoadBalancerMapping(arn string) taggingtypes.ResourceTagMapping {
+	return taggingtypes.ResourceTagMapping{
+		ResourceARN: aws.String(arn),
+		Tags:        []taggingtypes.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+	}
+}
+
+// newLoadBalancer returns an active internet-facing Application Load Balancer
+func newLoadBalancer(name string) elbv2types.LoadBalancer {
+	return elbv2types.LoadBalancer{
+		LoadBalancerName: aws.String(name),
+		LoadBalancerArn:  aws.String(loadBalancerARN(name)),
+		DNSName:          aws.String(name + "-123456789.ap-northeast-1.elb.amazonaws.com"),
+		Scheme:           elbv2types.LoadBalancerSchemeEnumInternetFacing,
+		Type:             elbv2types.LoadBalancerTypeEnumApplication,
+		VpcId:            aws.String("vpc-0123"),
+		State:            &elbv2types.LoadBalancerState{Code: elbv2types.LoadBalancerStateEnumActive},
+	}
+}
+
+// newListener returns a listener forwarding to the given target group
+func newListener(lbName string, protocol elbv2types.ProtocolEnum, port int32, targetGroup string) elbv2types.Listener {
+	return elbv2types.Listener{
+		ListenerArn:     aws.String("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:listener/app/" + lbName + "/0123456789abcdef/" + string(protocol)),
+		LoadBalancerArn: aws.String(loadBalancerARN(lbName)),
+		Protocol:        protocol,
+		Port:            aws.Int32(port),
+		DefaultActions: []elbv2types.Action{
+			{Type: elbv2types.ActionTypeEnumForward, TargetGroupArn: aws.String(targetGroupARN(targetGroup))},
+		},
+	}
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "elbv2_listener", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{
+		Region: "ap-northeast-1",
+		Filters: map[string]interface{}{
+			"protocols":             []interface{}{"HTTPS"},
+			"include_target_health": true,
+		},
+	}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"protocols": "HTTPS"},
+	})
+	assert.EqualError(t, err, "filters.protocols must be a list of strings")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"include_target_health": "yes"},
+	})
+	assert.EqualError(t, err, "filters.include_target_health must be a boolean")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockELBv2 := new(MockELBv2Client)
+		provider := newTestProvider(mockTagging, mockELBv2)
+
+		mockTagging.On("GetResources", mock.Anything, mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+			return len(input.ResourceTypeFilters) == 1 && input.ResourceTypeFilters[0] == "elasticloadbalancing:loadbalancer"
+		}), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				newLoadBalancerMapping(loadBalancerARN("web")),
+				newLoadBalancerMapping("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/classic"),
+				newLoadBalancerMapping(loadBalancerARN("api")),
+			},
+		}, nil)
+
+		internal := newLoadBalancer("api")
+		internal.Scheme = elbv2types.LoadBalancerSchemeEnumInternal
+		mockELBv2.On("DescribeLoadBalancers", mock.Anything, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+			LoadBalancerArns: []string{loadBalancerARN("web"), loadBalancerARN("api")},
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []elbv2types.LoadBalancer{internal, newLoadBalancer("web")},
+		}, nil)
+
+		mockELBv2.On("DescribeListeners", mock.Anything, &elasticloadbalancingv2.DescribeListenersInput{
+			LoadBalancerArn: aws.String(loadBalancerARN("web")),
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+			Listeners:  []elbv2types.Listener{newListener("web", elbv2types.ProtocolEnumHttps, 443, "web")},
+			NextMarker: aws.String("next"),
+		}, nil).Once()
+		mockELBv2.On("DescribeListeners", mock.Anything, &elasticloadbalancingv2.DescribeListenersInput{
+			LoadBalancerArn: aws.String(loadBalancerARN("web")),
+			Marker:          aws.String("next"),
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+			Listeners: []elbv2types.Listener{newListener("web", elbv2types.ProtocolEnumHttp, 80, "web")},
+		}, nil).Once()
+		mockELBv2.On("DescribeListeners", mock.Anything, &elasticloadbalancingv2.DescribeListenersInput{
+			LoadBalancerArn: aws.String(loadBalancerARN("api")),
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+			Listeners: []elbv2types.Listener{newListener("api", elbv2types.ProtocolEnumHttp, 8080, "api")},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"env": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+
+		// Listeners of a load balancer are ordered by port
+		resource := result[0]
+		assert.Equal(t, "web-123456789.ap-northeast-1.elb.amazonaws.com", resource.Host)
+		assert.Equal(t, 80, resource.Port)
+		assert.Equal(t, "prod", resource.Tags["env"])
+		assert.Equal(t, "web", resource.Metadata["LoadBalancerName"])
+		assert.Equal(t, loadBalancerARN("web"), resource.Metadata["LoadBalancerArn"])
+		assert.Equal(t, "application", resource.Metadata["LoadBalancerType"])
+		assert.Equal(t, "web-123456789.ap-northeast-1.elb.amazonaws.com", resource.Metadata["DNSName"])
+		assert.Equal(t, "internet-facing", resource.Metadata["Scheme"])
+		assert.Equal(t, "vpc-0123", resource.Metadata["VpcID"])
+		assert.Equal(t, "HTTP", resource.Metadata["Protocol"])
+		assert.Equal(t, "http://web-123456789.ap-northeast-1.elb.amazonaws.com:80", resource.Metadata["URL"])
+		assert.Equal(t, []string{targetGroupARN("web")}, resource.Metadata["TargetGroupArns"])
+		assert.NotContains(t, resource.Metadata, "Targets")
+
+		assert.Equal(t, 443, result[1].Port)
+		assert.Equal(t, "https://web-123456789.ap-northeast-1.elb.amazonaws.com:443", result[1].Metadata["URL"])
+
+		assert.Equal(t, "api", result[2].Metadata["LoadBalancerName"])
+		assert.Equal(t, "internal", result[2].Metadata["Scheme"])
+
+		mockTagging.AssertExpectations(t)
+		mockELBv2.AssertExpectations(t)
+		mockELBv2.AssertNotCalled(t, "DescribeTargetHealth", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("protocols filter and target health", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockELBv2 := new(MockELBv2Client)
+		provider := newTestProvider(mockTagging, mockELBv2)
+
+		mockTagging.On("GetResources", mock.Anything, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newLoadBalancerMapping(loadBalancerARN("web"))},
+		}, nil)
+		mockELBv2.On("DescribeLoadBalancers", mock.Anything, mock.Anything, mock.Anything).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []elbv2types.LoadBalancer{newLoadBalancer("web")},
+		}, nil)
+
+		// The HTTPS listener splits traffic between two target groups
+		https := newListener("web", elbv2types.ProtocolEnumHttps, 443, "web")
+		https.DefaultActions = []elbv2types.Action{
+			{
+				Type: elbv2types.ActionTypeEnumForward,
+				ForwardConfig: &elbv2types.ForwardActionConfig{
+					TargetGroups: []elbv2types.TargetGroupTuple{
+						{TargetGroupArn: aws.String(targetGroupARN("web"))},
+						{TargetGroupArn: aws.String(targetGroupARN("web-canary"))},
+					},
+				},
+			},
+		}
+		mockELBv2.On("DescribeListeners", mock.Anything, mock.Anything, mock.Anything).Return(&elasticloadbalancingv2.DescribeListenersOutput{
+			Listeners: []elbv2types.Listener{newListener("web", elbv2types.ProtocolEnumHttp, 80, "redirect"), https},
+		}, nil)
+
+		mockELBv2.On("DescribeTargetHealth", mock.Anything, &elasticloadbalancingv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN("web")),
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeTargetHealthOutput{
+			TargetHealthDescriptions: []elbv2types.TargetHealthDescription{
+				{
+					Target: &elbv2types.TargetDescription{Id: aws.String("i-0002"), Port: aws.Int32(8080), AvailabilityZone: aws.String("ap-northeast-1c")},
+					TargetHealth: &elbv2types.TargetHealth{
+						State:  elbv2types.TargetHealthStateEnumUnhealthy,
+						Reason: elbv2types.TargetHealthReasonEnumFailedHealthChecks,
+					},
+				},
+				{
+					Target:       &elbv2types.TargetDescription{Id: aws.String("i-0001"), Port: aws.Int32(8080), AvailabilityZone: aws.String("ap-northeast-1a")},
+					TargetHealth: &elbv2types.TargetHealth{State: elbv2types.TargetHealthStateEnumHealthy},
+				},
+			},
+		}, nil)
+		mockELBv2.On("DescribeTargetHealth", mock.Anything, &elasticloadbalancingv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN("web-canary")),
+		}, mock.Anything).Return(&elasticloadbalancingv2.DescribeTargetHealthOutput{
+			TargetHealthDescriptions: []elbv2types.TargetHealthDescription{
+				{
+					Target:       &elbv2types.TargetDescription{Id: aws.String("10.0.0.5"), Port: aws.Int32(8080)},
+					TargetHealth: &elbv2types.TargetHealth{State: elbv2types.TargetHealthStateEnumHealthy},
+				},
+				{
+					Target: &elbv2types.TargetDescription{Id: aws.String("10.0.0.6"), Port: aws.Int32(8080)},
+					TargetHealth: &elbv2types.TargetHealth{
+						State:  elbv2types.TargetHealthStateEnumDraining,
+						Reason: elbv2types.TargetHealthReasonEnumDeregistrationInProgress,
+					},
+				},
+			},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"protocols":             []interface{}{"https"},
+				"include_target_health": true,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+
+		resource := result[0]
+		assert.Equal(t, 443, resource.Port)
+		assert.Equal(t, []string{targetGroupARN("web"), targetGroupARN("web-canary")}, resource.Metadata["TargetGroupArns"])
+		assert.Equal(t, 2, resource.Metadata["HealthyTargetCount"])
+		assert.Equal(t, 1, resource.Metadata["UnhealthyTargetCount"])
+
+		targets, ok := resource.Metadata["Targets"].([]map[string]interface{})
+		require.True(t, ok)
+		require.Len(t, targets, 4)
+		// Targets are ordered by target group, ID and port regardless of the API order
+		assert.Equal(t, targetGroupARN("web-canary"), targets[0]["TargetGroupArn"])
+		assert.Equal(t, "draining", targets[1]["State"])
+		assert.Equal(t, "i-0001", targets[2]["ID"])
+		assert.Equal(t, map[string]interface{}{
+			"TargetGroupArn":   targetGroupARN("web"),
+			"ID":               "i-0002",
+			"Port":             8080,
+			"AvailabilityZone": "ap-northeast-1c",
+			"State":            "unhealthy",
+			"Reason":           "Target.FailedHealthChecks",
+		}, targets[3])
+
+		// Target groups of filtered out listeners are not described
+		mockELBv2.AssertNotCalled(t, "DescribeTargetHealth", mock.Anything, &elasticloadbalancingv2.DescribeTargetHealthInput{
+			TargetGroupArn: aws.String(targetGroupARN("redirect")),
+		}, mock.Anything)
+		mockELBv2.AssertExpectations(t)
+	})
+
+	t.Run("skips provisioning load balancers", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockELBv2 := new(MockELBv2Client)
+		provider := newTestProvider(mockTagging, mockELBv2)
+
+		provisioning := newLoadBalancer("web")
+		provisioning.State.Code = elbv2types.LoadBalancerStateEnumProvisioning
+
+		mockTagging.On("GetResources", mock.Anything, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newLoadBalancerMapping(loadBalancerARN("web"))},
+		}, nil)
+		mockELBv2.On("DescribeLoadBalancers", mock.Anything, mock.Anything, mock.Anything).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []elbv2types.LoadBalancer{provisioning},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockELBv2.AssertNotCalled(t, "DescribeListeners", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockELBv2 := new(MockELBv2Client)
+		provider := newTestProvider(mockTagging, mockELBv2)
+
+		mockTagging.On("GetResources", mock.Anything, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockELBv2.AssertNotCalled(t, "DescribeLoadBalancers", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("describe listeners error", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockELBv2 := new(MockELBv2Client)
+		provider := newTestProvider(mockTagging, mockELBv2)
+
+		mockTagging.On("GetResources", mock.Anything, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newLoadBalancerMapping(loadBalancerARN("web"))},
+		}, nil)
+		mockELBv2.On("DescribeLoadBalancers", mock.Anything, mock.Anything, mock.Anything).Return(&elasticloadbalancingv2.DescribeLoadBalancersOutput{
+			LoadBalancers: []elbv2types.LoadBalancer{newLoadBalancer("web")},
+		}, nil)
+		mockELBv2.On("DescribeListeners", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe listeners of "+loadBalancerARN("web"))
+	})
+}
+
+func TestIsELBv2ARN(t *testing.T) {
+	assert.True(t, isELBv2ARN("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/app/web/0123"))
+	assert.True(t, isELBv2ARN("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/net/tcp/0123"))
+	assert.True(t, isELBv2ARN("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/gwy/fw/0123"))
+	assert.False(t, isELBv2ARN("arn:aws:elasticloadbalancing:ap-northeast-1:123456789012:loadbalancer/classic"))
+}
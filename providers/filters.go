@@ -0,0 +1,95 @@
+package providers
+
+import "fmt"
+
+// BoolFilter returns the boolean value of filters[key], or false when it is not set
+func BoolFilter(filters map[string]interface{}, key string) (bool, error) {
+	v, ok := filters[key]
+	if !ok || v == nil {
+		return false, nil
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("filters.%s must be a boolean", key)
+	}
+	return b, nil
+}
+
+// StringListFilter returns the list of strings in filters[key], or nil when it is not set
+func StringListFilter(filters map[string]interface{}, key string) ([]string, error) {
+	v, ok := filters[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	switch list := v.(type) {
+	case []string:
+		return list, nil
+	case []interface{}:
+		result := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("filters.%s must be a list of strings", key)
+			}
+			result = append(result, s)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("filters.%s must be a list of strings", key)
+	}
+}
+
+// StringFilter returns the string value of filters[key], or an empty string when it is not set
+func StringFilter(filters map[string]interface{}, key string) (string, error) {
+	v, ok := filters[key]
+	if !ok || v == nil {
+		return "", nil
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("filters.%s must be a string", key)
+	}
+	return s, nil
+}
+
+// IntFilter returns the integer value of filters[key], or 0 when it is not set
+func IntFilter(filters map[string]interface{}, key string) (int, error) {
+	v, ok := filters[key]
+	if !ok || v == nil {
+		return 0, nil
+	}
+
+	i, ok := v.(int)
+	if !ok {
+		return 0, fmt.Errorf("filters.%s must be an integer", key)
+	}
+	return i, nil
+}
+
+// StringMapFilter returns the string map in filters[key], or nil when it is not set
+func StringMapFilter(filters map[string]interface{}, key string) (map[string]string, error) {
+	v, ok := filters[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+
+	switch m := v.(type) {
+	case map[string]string:
+		return m, nil
+	case map[string]interface{}:
+		result := make(map[string]string, len(m))
+		for k, item := range m {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("filters.%s must be a map of strings", key)
+			}
+			result[k] = s
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("filters.%s must be a map of strings", key)
+	}
+}
@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoolFilter(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		v, err := BoolFilter(nil, "enabled")
+		require.NoError(t, err)
+		assert.False(t, v)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		v, err := BoolFilter(map[string]interface{}{"enabled": true}, "enabled")
+		require.NoError(t, err)
+		assert.True(t, v)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := BoolFilter(map[string]interface{}{"enabled": "yes"}, "enabled")
+		assert.EqualError(t, err, "filters.enabled must be a boolean")
+	})
+}
+
+func TestStringListFilter(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		v, err := StringListFilter(map[string]interface{}{}, "engines")
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("list from yaml", func(t *testing.T) {
+		v, err := StringListFilter(map[string]interface{}{"engines": []interface{}{"mysql", "postgres"}}, "engines")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"mysql", "postgres"}, v)
+	})
+
+	t.Run("string slice", func(t *testing.T) {
+		v, err := StringListFilter(map[string]interface{}{"engines": []string{"mysql"}}, "engines")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"mysql"}, v)
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		_, err := StringListFilter(map[string]interface{}{"engines": []interface{}{"mysql", 1}}, "engines")
+		assert.EqualError(t, err, "filters.engines must be a list of strings")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := StringListFilter(map[string]interface{}{"engines": "mysql"}, "engines")
+		assert.EqualError(t, err, "filters.engines must be a list of strings")
+	})
+}
+
+func TestStringFilter(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		v, err := StringFilter(nil, "name")
+		require.NoError(t, err)
+		assert.Empty(t, v)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		v, err := StringFilter(map[string]interface{}{"name": "value"}, "name")
+		require.NoError(t, err)
+		assert.Equal(t, "value", v)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := StringFilter(map[string]interface{}{"name": 1}, "name")
+		assert.EqualError(t, err, "filters.name must be a string")
+	})
+}
+
+func TestIntFilter(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		v, err := IntFilter(nil, "port")
+		require.NoError(t, err)
+		assert.Zero(t, v)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		v, err := IntFilter(map[string]interface{}{"port": 8080}, "port")
+		require.NoError(t, err)
+		assert.Equal(t, 8080, v)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := IntFilter(map[string]interface{}{"port": "8080"}, "port")
+		assert.EqualError(t, err, "filters.port must be an integer")
+	})
+}
+
+func TestStringMapFilter(t *testing.T) {
+	t.Run("not set", func(t *testing.T) {
+		v, err := StringMapFilter(nil, "attributes")
+		require.NoError(t, err)
+		assert.Nil(t, v)
+	})
+
+	t.Run("map from yaml", func(t *testing.T) {
+		v, err := StringMapFilter(map[string]interface{}{"attributes": map[string]interface{}{"stage": "prod"}}, "attributes")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"stage": "prod"}, v)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := StringMapFilter(map[string]interface{}{"attributes": map[string]interface{}{"version": 1}}, "attributes")
+		assert.EqualError(t, err, "filters.attributes must be a map of strings")
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		_, err := StringMapFilter(map[string]interface{}{"attributes": "stage=prod"}, "attributes")
+		assert.EqualError(t, err, "filters.attributes must be a map of strings")
+	})
+}
@@ -17,5 +17,7 @@ type Provider interface {
 // ProviderConfig represents configuration for a provider
 type ProviderConfig struct {
 	Region  string
+	Profile string // Shared config profile used to load credentials (optional)
+	RoleARN string // IAM role assumed for API calls (optional)
 	Filters map[string]interface{}
 }
@@ -0,0 +1,249 @@
+package memorydb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	memorydbtypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "memorydb"
+
+const (
+	// listTagsConcurrency is the maximum number of ListTags calls in flight
+	listTagsConcurrency = 5
+	// describePageSize is the MaxResults value used when listing clusters
+	describePageSize = 100
+)
+
+// Provider implements the providers.Provider interface for Amazon MemoryDB
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	MemoryDB MemoryDBAPI
+}
+
+// MemoryDBAPI defines the MemoryDB API interface
+type MemoryDBAPI interface {
+	DescribeClusters(ctx context.Context, params *memorydb.DescribeClustersInput, optFns ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error)
+	ListTags(ctx context.Context, params *memorydb.ListTagsInput, optFns ...func(*memorydb.Options)) (*memorydb.ListTagsOutput, error)
+}
+
+// NewProvider creates a new MemoryDB provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new MemoryDB provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		MemoryDB: memorydb.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves MemoryDB nodes based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting MemoryDB discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	// MemoryDB clusters are listed with their shards and filtered by tags afterwards
+	clusters, err := listClusters(ctx, clients.MemoryDB)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusters) == 0 {
+		slog.Info("No MemoryDB clusters found")
+		return []providers.Resource{}, nil
+	}
+
+	// Get tags of each cluster; results keep the order of the clusters
+	tagsPerCluster, err := providers.ParallelMap(ctx, clusters, listTagsConcurrency, func(ctx context.Context, cluster memorydbtypes.Cluster) (map[string]string, error) {
+		return listTags(ctx, clients.MemoryDB, aws.ToString(cluster.ARN))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	matched := 0
+	for i, cluster := range clusters {
+		if !awsutil.MatchTags(tagsPerCluster[i], tags) {
+			continue
+		}
+		matched++
+
+		nodes := extractNodesFromCluster(cluster, tagsPerCluster[i])
+		slog.Debug("Extracted nodes from cluster",
+			"cluster_name", aws.ToString(cluster.Name),
+			"nodes_count", len(nodes))
+		result = append(result, nodes...)
+	}
+
+	slog.Info("MemoryDB discovery completed", "matched_clusters", matched, "total_nodes", len(result))
+	return result, nil
+}
+
+// listClusters lists all clusters in the region with shard details, following NextToken
+func listClusters(ctx context.Context, client MemoryDBAPI) ([]memorydbtypes.Cluster, error) {
+	var result []memorydbtypes.Cluster
+	var nextToken *string
+
+	for {
+		input := &memorydb.DescribeClustersInput{
+			ShowShardDetails: aws.Bool(true),
+			MaxResults:       aws.Int32(describePageSize),
+			NextToken:        nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *memorydb.DescribeClustersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeClusters API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeClusters(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe MemoryDB clusters: %w", err)
+		}
+		result = append(result, resp.Clusters...)
+
+		slog.Debug("Retrieved MemoryDB clusters page",
+			"page_count", len(resp.Clusters),
+			"total_count", len(result))
+
+		if aws.ToString(resp.NextToken) == "" {
+			return result, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// listTags returns the tags of a MemoryDB resource
+func listTags(ctx context.Context, client MemoryDBAPI, arn string) (map[string]string, error) {
+	input := &memorydb.ListTagsInput{
+		ResourceArn: aws.String(arn),
+	}
+
+	// Catch panic and convert to error
+	var resp *memorydb.ListTagsOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during ListTags API call: %v", r)
+			}
+		}()
+		resp, err = client.ListTags(ctx, input)
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", arn, err)
+	}
+
+	tags := make(map[string]string, len(resp.TagList))
+	for _, tag := range resp.TagList {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+	return tags, nil
+}
+
+// extractNodesFromCluster extracts all nodes from the shards of a cluster
+func extractNodesFromCluster(cluster memorydbtypes.Cluster, tags map[string]string) []providers.Resource {
+	var result []providers.Resource
+	clusterName := aws.ToString(cluster.Name)
+
+	var clusterEndpoint string
+	if cluster.ClusterEndpoint != nil {
+		clusterEndpoint = fmt.Sprintf("%s:%d", aws.ToString(cluster.ClusterEndpoint.Address), cluster.ClusterEndpoint.Port)
+	}
+
+	for _, shard := range cluster.Shards {
+		shardName := aws.ToString(shard.Name)
+		slog.Debug("Processing shard",
+			"shard_name", shardName,
+			"nodes_count", len(shard.Nodes))
+
+		for _, node := range shard.Nodes {
+			if node.Endpoint == nil {
+				slog.Warn("Node has no endpoint",
+					"cluster_name", clusterName,
+					"shard_name", shardName,
+					"node_name", aws.ToString(node.Name))
+				continue
+			}
+
+			resource := providers.Resource{
+				Host: aws.ToString(node.Endpoint.Address),
+				Port: int(node.Endpoint.Port),
+				Tags: tags,
+				Metadata: map[string]interface{}{
+					"ClusterName":      clusterName,
+					"ShardName":        shardName,
+					"NodeName":         aws.ToString(node.Name),
+					"AvailabilityZone": aws.ToString(node.AvailabilityZone),
+					"TLSEnabled":       aws.ToBool(cluster.TLSEnabled),
+					"Engine":           aws.ToString(cluster.Engine),
+					"EngineVersion":    aws.ToString(cluster.EngineVersion),
+					"NodeType":         aws.ToString(cluster.NodeType),
+					"ClusterEndpoint":  clusterEndpoint,
+				},
+			}
+
+			slog.Debug("Extracted node",
+				"host", resource.Host,
+				"port", resource.Port,
+				"shard", shardName,
+				"node", aws.ToString(node.Name))
+
+			result = append(result, resource)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,202 @@
+package memorydb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	memorydbtypes "github.com/aws/aws-sdk-go-v2/service/memorydb/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMemoryDBClient is a mock implementation of MemoryDBAPI
+type MockMemoryDBClient struct {
+	mock.Mock
+}
+
+func (m *MockMemoryDBClient) DescribeClusters(ctx context.Context, params *memorydb.DescribeClustersInput, optFns ...func(*memorydb.Options)) (*memorydb.DescribeClustersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memorydb.DescribeClustersOutput), args.Error(1)
+}
+
+func (m *MockMemoryDBClient) ListTags(ctx context.Context, params *memorydb.ListTagsInput, optFns ...func(*memorydb.Options)) (*memorydb.ListTagsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*memorydb.ListTagsOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client MemoryDBAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{MemoryDB: client}, nil
+	})
+}
+
+// newCluster returns a TLS-enabled cluster with one shard of two nodes
+func newCluster(name string) memorydbtypes.Cluster {
+	node := func(nodeName, az string) memorydbtypes.Node {
+		return memorydbtypes.Node{
+			Name:             aws.String(nodeName),
+			AvailabilityZone: aws.String(az),
+			Endpoint: &memorydbtypes.Endpoint{
+				Address: aws.String(name + "-0001-" + nodeName + ".abc123.memorydb.ap-northeast-1.amazonaws.com"),
+				Port:    6379,
+			},
+		}
+	}
+
+	return memorydbtypes.Cluster{
+		Name:          aws.String(name),
+		ARN:           aws.String("arn:aws:memorydb:ap-northeast-1:123456789012:cluster/" + name),
+		TLSEnabled:    aws.Bool(true),
+		Engine:        aws.String("valkey"),
+		EngineVersion: aws.String("7.3"),
+		NodeType:      aws.String("db.r7g.large"),
+		ClusterEndpoint: &memorydbtypes.Endpoint{
+			Address: aws.String("clustercfg." + name + ".abc123.memorydb.ap-northeast-1.amazonaws.com"),
+			Port:    6379,
+		},
+		Shards: []memorydbtypes.Shard{
+			{
+				Name:  aws.String("0001"),
+				Nodes: []memorydbtypes.Node{node("001", "ap-northeast-1a"), node("002", "ap-northeast-1c")},
+			},
+		},
+	}
+}
+
+// listTagsOutput returns a ListTags response with the given key/value pairs
+func listTagsOutput(kv ...string) *memorydb.ListTagsOutput {
+	out := &memorydb.ListTagsOutput{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		out.TagList = append(out.TagList, memorydbtypes.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return out
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "memorydb", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"tags": "invalid"},
+	})
+	assert.EqualError(t, err, "filters.tags must be a map")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockMemoryDB := new(MockMemoryDBClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockMemoryDB)
+
+		mockMemoryDB.On("DescribeClusters", mock.Anything, mock.MatchedBy(func(input *memorydb.DescribeClustersInput) bool {
+			return aws.ToBool(input.ShowShardDetails) && input.NextToken == nil
+		}), mock.Anything).Return(&memorydb.DescribeClustersOutput{
+			Clusters:  []memorydbtypes.Cluster{newCluster("sessions")},
+			NextToken: aws.String("next"),
+		}, nil).Once()
+		mockMemoryDB.On("DescribeClusters", mock.Anything, mock.MatchedBy(func(input *memorydb.DescribeClustersInput) bool {
+			return aws.ToBool(input.ShowShardDetails) && aws.ToString(input.NextToken) == "next"
+		}), mock.Anything).Return(&memorydb.DescribeClustersOutput{
+			Clusters: []memorydbtypes.Cluster{newCluster("staging-sessions"), newCluster("cache")},
+		}, nil).Once()
+
+		mockMemoryDB.On("ListTags", mock.Anything, &memorydb.ListTagsInput{
+			ResourceArn: aws.String("arn:aws:memorydb:ap-northeast-1:123456789012:cluster/sessions"),
+		}, mock.Anything).Return(listTagsOutput("Environment", "production", "Team", "backend"), nil)
+		mockMemoryDB.On("ListTags", mock.Anything, &memorydb.ListTagsInput{
+			ResourceArn: aws.String("arn:aws:memorydb:ap-northeast-1:123456789012:cluster/staging-sessions"),
+		}, mock.Anything).Return(listTagsOutput("Environment", "staging"), nil)
+		mockMemoryDB.On("ListTags", mock.Anything, &memorydb.ListTagsInput{
+			ResourceArn: aws.String("arn:aws:memorydb:ap-northeast-1:123456789012:cluster/cache"),
+		}, mock.Anything).Return(listTagsOutput("Environment", "production"), nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"Environment": "production"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 4)
+
+		resource := result[0]
+		assert.Equal(t, "sessions-0001-001.abc123.memorydb.ap-northeast-1.amazonaws.com", resource.Host)
+		assert.Equal(t, 6379, resource.Port)
+		assert.Equal(t, "production", resource.Tags["Environment"])
+		assert.Equal(t, "backend", resource.Tags["Team"])
+		assert.Equal(t, "sessions", resource.Metadata["ClusterName"])
+		assert.Equal(t, "0001", resource.Metadata["ShardName"])
+		assert.Equal(t, "001", resource.Metadata["NodeName"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, true, resource.Metadata["TLSEnabled"])
+		assert.Equal(t, "valkey", resource.Metadata["Engine"])
+		assert.Equal(t, "7.3", resource.Metadata["EngineVersion"])
+		assert.Equal(t, "db.r7g.large", resource.Metadata["NodeType"])
+		assert.Equal(t, "clustercfg.sessions.abc123.memorydb.ap-northeast-1.amazonaws.com:6379", resource.Metadata["ClusterEndpoint"])
+
+		assert.Equal(t, "002", result[1].Metadata["NodeName"])
+		assert.Equal(t, "cache", result[2].Metadata["ClusterName"])
+		assert.Equal(t, "cache", result[3].Metadata["ClusterName"])
+
+		mockMemoryDB.AssertExpectations(t)
+	})
+
+	t.Run("no clusters", func(t *testing.T) {
+		mockMemoryDB := new(MockMemoryDBClient)
+		provider := newTestProvider(mockMemoryDB)
+
+		mockMemoryDB.On("DescribeClusters", mock.Anything, mock.Anything, mock.Anything).Return(&memorydb.DescribeClustersOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockMemoryDB.AssertNotCalled(t, "ListTags", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockMemoryDB := new(MockMemoryDBClient)
+		provider := newTestProvider(mockMemoryDB)
+
+		mockMemoryDB.On("DescribeClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe MemoryDB clusters")
+	})
+
+	t.Run("list tags error", func(t *testing.T) {
+		mockMemoryDB := new(MockMemoryDBClient)
+		provider := newTestProvider(mockMemoryDB)
+
+		mockMemoryDB.On("DescribeClusters", mock.Anything, mock.Anything, mock.Anything).Return(&memorydb.DescribeClustersOutput{
+			Clusters: []memorydbtypes.Cluster{newCluster("sessions")},
+		}, nil)
+		mockMemoryDB.On("ListTags", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list tags of arn:aws:memorydb:ap-northeast-1:123456789012:cluster/sessions")
+	})
+}
@@ -0,0 +1,445 @@
+package msk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	kafkatypes "github.com/aws/aws-sdk-go-v2/service/kafka/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "msk_kafka"
+
+// authenticationFilter selects the listener whose brokers and bootstrap string are returned
+const authenticationFilter = "authentication"
+
+// Authentication methods accepted in filters.authentication
+const (
+	authenticationPlaintext = "plaintext"
+	authenticationTLS       = "tls"
+	authenticationSASLSCRAM = "sasl_scram"
+	authenticationSASLIAM   = "sasl_iam"
+)
+
+// defaultAuthentication is used when filters.authentication is not set
+const defaultAuthentication = authenticationTLS
+
+const (
+	// jmxExporterPort is the port of the Open Monitoring JMX Exporter on every broker
+	jmxExporterPort = 11001
+	// nodeExporterPort is the port of the Open Monitoring Node Exporter on every broker
+	nodeExporterPort = 11002
+)
+
+const (
+	// describeConcurrency is the maximum number of clusters whose nodes are fetched in parallel
+	describeConcurrency = 5
+	// listPageSize is the MaxResults value used when listing clusters and nodes
+	listPageSize = 100
+)
+
+// Provider implements the providers.Provider interface for Amazon MSK
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	Kafka KafkaAPI
+	EC2   EC2API
+}
+
+// KafkaAPI defines the MSK API interface
+type KafkaAPI interface {
+	ListClustersV2(ctx context.Context, params *kafka.ListClustersV2Input, optFns ...func(*kafka.Options)) (*kafka.ListClustersV2Output, error)
+	ListNodes(ctx context.Context, params *kafka.ListNodesInput, optFns ...func(*kafka.Options)) (*kafka.ListNodesOutput, error)
+	GetBootstrapBrokers(ctx context.Context, params *kafka.GetBootstrapBrokersInput, optFns ...func(*kafka.Options)) (*kafka.GetBootstrapBrokersOutput, error)
+}
+
+// EC2API defines the EC2 API interface used to resolve broker availability zones
+type EC2API interface {
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+}
+
+// clusterBrokers holds the brokers and bootstrap string of a cluster
+type clusterBrokers struct {
+	brokers          []kafkatypes.BrokerNodeInfo
+	instanceTypes    map[float64]string
+	bootstrapBrokers string
+}
+
+// NewProvider creates a new MSK provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new MSK provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		Kafka: kafka.NewFromConfig(awsCfg),
+		EC2:   ec2.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	authentication, err := providers.StringFilter(cfg.Filters, authenticationFilter)
+	if err != nil {
+		return err
+	}
+	switch authentication {
+	case "", authenticationPlaintext, authenticationTLS, authenticationSASLSCRAM, authenticationSASLIAM:
+		return nil
+	default:
+		return fmt.Errorf("filters.%s must be one of %s, %s, %s or %s", authenticationFilter,
+			authenticationPlaintext, authenticationTLS, authenticationSASLSCRAM, authenticationSASLIAM)
+	}
+}
+
+// Discover retrieves MSK brokers based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting MSK discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	authentication, _ := providers.StringFilter(cfg.Filters, authenticationFilter)
+	if authentication == "" {
+		authentication = defaultAuthentication
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags, "authentication", authentication)
+
+	// Clusters are listed with their tags and filtered afterwards
+	allClusters, err := listClusters(ctx, clients.Kafka)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []kafkatypes.Cluster
+	for _, cluster := range allClusters {
+		if !awsutil.MatchTags(cluster.Tags, tags) {
+			continue
+		}
+		switch cluster.State {
+		case kafkatypes.ClusterStateCreating, kafkatypes.ClusterStateDeleting, kafkatypes.ClusterStateFailed:
+			slog.Debug("Skipping MSK cluster",
+				"cluster_name", aws.ToString(cluster.ClusterName),
+				"state", cluster.State)
+			continue
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	if len(clusters) == 0 {
+		slog.Info("No MSK clusters found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found MSK clusters by tags", "count", len(clusters))
+
+	// Get brokers and bootstrap strings in parallel; results keep the order of the clusters
+	brokersPerCluster, err := providers.ParallelMap(ctx, clusters, describeConcurrency, func(ctx context.Context, cluster kafkatypes.Cluster) (clusterBrokers, error) {
+		return describeClusterBrokers(ctx, clients.Kafka, aws.ToString(cluster.ClusterArn), authentication)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var subnetIDs []string
+	for _, cb := range brokersPerCluster {
+		for _, broker := range cb.brokers {
+			subnetIDs = append(subnetIDs, aws.ToString(broker.ClientSubnet))
+		}
+	}
+	subnetToAZ, err := describeSubnetAZs(ctx, clients.EC2, subnetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for i, cluster := range clusters {
+		brokers := extractBrokers(cluster, brokersPerCluster[i], subnetToAZ)
+		slog.Debug("Extracted brokers from cluster",
+			"cluster_name", aws.ToString(cluster.ClusterName),
+			"brokers_count", len(brokers))
+		result = append(result, brokers...)
+	}
+
+	slog.Info("MSK discovery completed", "total_brokers", len(result))
+	return result, nil
+}
+
+// listClusters lists all provisioned clusters in the region, following NextToken
+func listClusters(ctx context.Context, client KafkaAPI) ([]kafkatypes.Cluster, error) {
+	var result []kafkatypes.Cluster
+	var nextToken *string
+
+	for {
+		input := &kafka.ListClustersV2Input{
+			ClusterTypeFilter: aws.String(string(kafkatypes.ClusterTypeProvisioned)),
+			MaxResults:        aws.Int32(listPageSize),
+			NextToken:         nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *kafka.ListClustersV2Output
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListClustersV2 API call: %v", r)
+				}
+			}()
+			resp, err = client.ListClustersV2(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to list MSK clusters: %w", err)
+		}
+		result = append(result, resp.ClusterInfoList...)
+
+		if aws.ToString(resp.NextToken) == "" {
+			return result, nil
+		}
+		nextToken = resp.NextToken
+	}
+}
+
+// describeClusterBrokers returns the brokers of a cluster ordered by broker ID,
+// and its bootstrap string for the authentication method
+func describeClusterBrokers(ctx context.Context, client KafkaAPI, clusterARN, authentication string) (clusterBrokers, error) {
+	result := clusterBrokers{instanceTypes: make(map[float64]string)}
+	var nextToken *string
+
+	for {
+		input := &kafka.ListNodesInput{
+			ClusterArn: aws.String(clusterARN),
+			MaxResults: aws.Int32(listPageSize),
+			NextToken:  nextToken,
+		}
+
+		// Catch panic and convert to error
+		var resp *kafka.ListNodesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during ListNodes API call: %v", r)
+				}
+			}()
+			resp, err = client.ListNodes(ctx, input)
+		}()
+
+		if err != nil {
+			return result, fmt.Errorf("failed to list nodes of %s: %w", clusterARN, err)
+		}
+
+		for _, node := range resp.NodeInfoList {
+			if node.NodeType != kafkatypes.NodeTypeBroker || node.BrokerNodeInfo == nil {
+				continue
+			}
+			result.brokers = append(result.brokers, *node.BrokerNodeInfo)
+			result.instanceTypes[aws.ToFloat64(node.BrokerNodeInfo.BrokerId)] = aws.ToString(node.InstanceType)
+		}
+
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	sort.Slice(result.brokers, func(i, j int) bool {
+		return aws.ToFloat64(result.brokers[i].BrokerId) < aws.ToFloat64(result.brokers[j].BrokerId)
+	})
+
+	// Catch panic and convert to error
+	var resp *kafka.GetBootstrapBrokersOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during GetBootstrapBrokers API call: %v", r)
+			}
+		}()
+		resp, err = client.GetBootstrapBrokers(ctx, &kafka.GetBootstrapBrokersInput{
+			ClusterArn: aws.String(clusterARN),
+		})
+	}()
+
+	if err != nil {
+		return result, fmt.Errorf("failed to get bootstrap brokers of %s: %w", clusterARN, err)
+	}
+	result.bootstrapBrokers = bootstrapBrokerString(resp, authentication)
+
+	return result, nil
+}
+
+// bootstrapBrokerString returns the bootstrap string for an authentication method
+func bootstrapBrokerString(resp *kafka.GetBootstrapBrokersOutput, authentication string) string {
+	switch authentication {
+	case authenticationPlaintext:
+		return aws.ToString(resp.BootstrapBrokerString)
+	case authenticationSASLSCRAM:
+		return aws.ToString(resp.BootstrapBrokerStringSaslScram)
+	case authenticationSASLIAM:
+		return aws.ToString(resp.BootstrapBrokerStringSaslIam)
+	default:
+		return aws.ToString(resp.BootstrapBrokerStringTls)
+	}
+}
+
+// bootstrapPort returns the port of the first broker in a bootstrap string
+func bootstrapPort(bootstrapBrokers string) (int, error) {
+	first, _, _ := strings.Cut(bootstrapBrokers, ",")
+	_, port, err := net.SplitHostPort(first)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(port)
+}
+
+// describeSubnetAZs returns the availability zone of each subnet
+func describeSubnetAZs(ctx context.Context, client EC2API, subnetIDs []string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, id := range subnetIDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	// Catch panic and convert to error
+	var resp *ec2.DescribeSubnetsOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during DescribeSubnets API call: %v", r)
+			}
+		}()
+		resp, err = client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+			SubnetIds: ids,
+		})
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe broker subnets: %w", err)
+	}
+
+	for _, subnet := range resp.Subnets {
+		result[aws.ToString(subnet.SubnetId)] = aws.ToString(subnet.AvailabilityZone)
+	}
+	return result, nil
+}
+
+// extractBrokers returns a resource for each broker of a cluster
+func extractBrokers(cluster kafkatypes.Cluster, cb clusterBrokers, subnetToAZ map[string]string) []providers.Resource {
+	var result []providers.Resource
+	clusterName := aws.ToString(cluster.ClusterName)
+
+	if cb.bootstrapBrokers == "" {
+		slog.Warn("MSK cluster has no bootstrap brokers for the authentication method",
+			"cluster_name", clusterName)
+		return result
+	}
+
+	port, err := bootstrapPort(cb.bootstrapBrokers)
+	if err != nil {
+		slog.Warn("Failed to parse bootstrap brokers",
+			"cluster_name", clusterName,
+			"bootstrap_brokers", cb.bootstrapBrokers,
+			"error", err)
+		return result
+	}
+
+	var kafkaVersion string
+	var jmxExporterEnabled bool
+	if cluster.Provisioned != nil {
+		if cluster.Provisioned.CurrentBrokerSoftwareInfo != nil {
+			kafkaVersion = aws.ToString(cluster.Provisioned.CurrentBrokerSoftwareInfo.KafkaVersion)
+		}
+		if om := cluster.Provisioned.OpenMonitoring; om != nil && om.Prometheus != nil && om.Prometheus.JmxExporter != nil {
+			jmxExporterEnabled = aws.ToBool(om.Prometheus.JmxExporter.EnabledInBroker)
+		}
+	}
+
+	for _, broker := range cb.brokers {
+		brokerID := int(aws.ToFloat64(broker.BrokerId))
+		if len(broker.Endpoints) == 0 {
+			slog.Warn("MSK broker has no endpoint",
+				"cluster_name", clusterName,
+				"broker_id", brokerID)
+			continue
+		}
+
+		resource := providers.Resource{
+			Host: broker.Endpoints[0],
+			Port: port,
+			Tags: cluster.Tags,
+			Metadata: map[string]interface{}{
+				"ClusterName":        clusterName,
+				"ClusterArn":         aws.ToString(cluster.ClusterArn),
+				"BrokerID":           brokerID,
+				"AvailabilityZone":   subnetToAZ[aws.ToString(broker.ClientSubnet)],
+				"ClientSubnet":       aws.ToString(broker.ClientSubnet),
+				"ClientVpcIpAddress": aws.ToString(broker.ClientVpcIpAddress),
+				"InstanceType":       cb.instanceTypes[aws.ToFloat64(broker.BrokerId)],
+				"KafkaVersion":       kafkaVersion,
+				"JMXExporterPort":    jmxExporterPort,
+				"NodeExporterPort":   nodeExporterPort,
+				"JMXExporterEnabled": jmxExporterEnabled,
+				"BootstrapBrokers":   cb.bootstrapBrokers,
+			},
+		}
+
+		slog.Debug("Extracted broker",
+			"host", resource.Host,
+			"port", resource.Port,
+			"cluster_name", clusterName,
+			"broker_id", brokerID)
+
+		result = append(result, resource)
+	}
+
+	return result
+}
@@ -0,0 +1,317 @@
+package msk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	kafkatypes "github.com/aws/aws-sdk-go-v2/service/kafka/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKafkaClient is a mock implementation of KafkaAPI
+type MockKafkaClient struct {
+	mock.Mock
+}
+
+func (m *MockKafkaClient) ListClustersV2(ctx context.Context, params *kafka.ListClustersV2Input, optFns ...func(*kafka.Options)) (*kafka.ListClustersV2Output, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kafka.ListClustersV2Output), args.Error(1)
+}
+
+func (m *MockKafkaClient) ListNodes(ctx context.Context, params *kafka.ListNodesInput, optFns ...func(*kafka.Options)) (*kafka.ListNodesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kafka.ListNodesOutput), args.Error(1)
+}
+
+func (m *MockKafkaClient) GetBootstrapBrokers(ctx context.Context, params *kafka.GetBootstrapBrokersInput, optFns ...func(*kafka.Options)) (*kafka.GetBootstrapBrokersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kafka.GetBootstrapBrokersOutput), args.Error(1)
+}
+
+// MockEC2Client is a mock implementation of EC2API
+type MockEC2Client struct {
+	mock.Mock
+}
+
+func (m *MockEC2Client) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ec2.DescribeSubnetsOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mocks
+func newTestProvider(kafkaClient KafkaAPI, ec2Client EC2API) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			Kafka: kafkaClient,
+			EC2:   ec2Client,
+		}, nil
+	})
+}
+
+const testClusterARN = "arn:aws:kafka:ap-northeast-1:123456789012:cluster/events/abcd-1234"
+
+// newCluster returns an active provisioned cluster with the JMX Exporter enabled
+func newCluster(name, arn string, tags map[string]string) kafkatypes.Cluster {
+	return kafkatypes.Cluster{
+		ClusterName: aws.String(name),
+		ClusterArn:  aws.String(arn),
+		ClusterType: kafkatypes.ClusterTypeProvisioned,
+		State:       kafkatypes.ClusterStateActive,
+		Tags:        tags,
+		Provisioned: &kafkatypes.Provisioned{
+			CurrentBrokerSoftwareInfo: &kafkatypes.BrokerSoftwareInfo{KafkaVersion: aws.String("3.6.0")},
+			OpenMonitoring: &kafkatypes.OpenMonitoringInfo{
+				Prometheus: &kafkatypes.PrometheusInfo{
+					JmxExporter: &kafkatypes.JmxExporterInfo{EnabledInBroker: aws.Bool(true)},
+				},
+			},
+		},
+	}
+}
+
+// newBrokerNode returns a broker node in the given subnet
+func newBrokerNode(id float64, host, subnet string) kafkatypes.NodeInfo {
+	return kafkatypes.NodeInfo{
+		NodeType:     kafkatypes.NodeTypeBroker,
+		InstanceType: aws.String("kafka.m5.large"),
+		BrokerNodeInfo: &kafkatypes.BrokerNodeInfo{
+			BrokerId:           aws.Float64(id),
+			ClientSubnet:       aws.String(subnet),
+			ClientVpcIpAddress: aws.String("10.0.0.1"),
+			Endpoints:          []string{host},
+		},
+	}
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "msk_kafka", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"}))
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"authentication": "sasl_iam"},
+	}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"authentication": "kerberos"},
+	})
+	assert.EqualError(t, err, "filters.authentication must be one of plaintext, tls, sasl_scram or sasl_iam")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockKafka := new(MockKafkaClient)
+		mockEC2 := new(MockEC2Client)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockKafka, mockEC2)
+
+		creating := newCluster("creating", "arn:aws:kafka:ap-northeast-1:123456789012:cluster/creating/efgh-5678", map[string]string{"env": "prod"})
+		creating.State = kafkatypes.ClusterStateCreating
+
+		mockKafka.On("ListClustersV2", mock.Anything, mock.MatchedBy(func(input *kafka.ListClustersV2Input) bool {
+			return aws.ToString(input.ClusterTypeFilter) == "PROVISIONED"
+		}), mock.Anything).Return(&kafka.ListClustersV2Output{
+			ClusterInfoList: []kafkatypes.Cluster{
+				newCluster("events", testClusterARN, map[string]string{"env": "prod", "team": "data"}),
+				newCluster("staging-events", "arn:aws:kafka:ap-northeast-1:123456789012:cluster/staging-events/ijkl-9012", map[string]string{"env": "staging"}),
+				creating,
+			},
+		}, nil)
+
+		mockKafka.On("ListNodes", mock.Anything, mock.MatchedBy(func(input *kafka.ListNodesInput) bool {
+			return aws.ToString(input.ClusterArn) == testClusterARN && input.NextToken == nil
+		}), mock.Anything).Return(&kafka.ListNodesOutput{
+			NodeInfoList: []kafkatypes.NodeInfo{
+				newBrokerNode(2, "b-2.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com", "subnet-c"),
+				{NodeType: kafkatypes.NodeType("ZOOKEEPER")},
+			},
+			NextToken: aws.String("next"),
+		}, nil).Once()
+		mockKafka.On("ListNodes", mock.Anything, mock.MatchedBy(func(input *kafka.ListNodesInput) bool {
+			return aws.ToString(input.NextToken) == "next"
+		}), mock.Anything).Return(&kafka.ListNodesOutput{
+			NodeInfoList: []kafkatypes.NodeInfo{
+				newBrokerNode(1, "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com", "subnet-a"),
+			},
+		}, nil).Once()
+
+		bootstrap := "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9094,b-2.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9094"
+		mockKafka.On("GetBootstrapBrokers", mock.Anything, &kafka.GetBootstrapBrokersInput{
+			ClusterArn: aws.String(testClusterARN),
+		}, mock.Anything).Return(&kafka.GetBootstrapBrokersOutput{
+			BootstrapBrokerString:    aws.String("b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9092"),
+			BootstrapBrokerStringTls: aws.String(bootstrap),
+		}, nil)
+
+		mockEC2.On("DescribeSubnets", mock.Anything, &ec2.DescribeSubnetsInput{
+			SubnetIds: []string{"subnet-a", "subnet-c"},
+		}, mock.Anything).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []ec2types.Subnet{
+				{SubnetId: aws.String("subnet-a"), AvailabilityZone: aws.String("ap-northeast-1a")},
+				{SubnetId: aws.String("subnet-c"), AvailabilityZone: aws.String("ap-northeast-1c")},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"env": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com", resource.Host)
+		assert.Equal(t, 9094, resource.Port)
+		assert.Equal(t, "data", resource.Tags["team"])
+		assert.Equal(t, "events", resource.Metadata["ClusterName"])
+		assert.Equal(t, testClusterARN, resource.Metadata["ClusterArn"])
+		assert.Equal(t, 1, resource.Metadata["BrokerID"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "subnet-a", resource.Metadata["ClientSubnet"])
+		assert.Equal(t, "10.0.0.1", resource.Metadata["ClientVpcIpAddress"])
+		assert.Equal(t, "kafka.m5.large", resource.Metadata["InstanceType"])
+		assert.Equal(t, "3.6.0", resource.Metadata["KafkaVersion"])
+		assert.Equal(t, 11001, resource.Metadata["JMXExporterPort"])
+		assert.Equal(t, 11002, resource.Metadata["NodeExporterPort"])
+		assert.Equal(t, true, resource.Metadata["JMXExporterEnabled"])
+		assert.Equal(t, bootstrap, resource.Metadata["BootstrapBrokers"])
+
+		assert.Equal(t, 2, result[1].Metadata["BrokerID"])
+		assert.Equal(t, "ap-northeast-1c", result[1].Metadata["AvailabilityZone"])
+
+		mockKafka.AssertExpectations(t)
+		mockEC2.AssertExpectations(t)
+	})
+
+	t.Run("authentication selects the bootstrap string", func(t *testing.T) {
+		mockKafka := new(MockKafkaClient)
+		mockEC2 := new(MockEC2Client)
+
+		provider := newTestProvider(mockKafka, mockEC2)
+
+		mockKafka.On("ListClustersV2", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListClustersV2Output{
+			ClusterInfoList: []kafkatypes.Cluster{newCluster("events", testClusterARN, nil)},
+		}, nil)
+		mockKafka.On("ListNodes", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListNodesOutput{
+			NodeInfoList: []kafkatypes.NodeInfo{
+				newBrokerNode(1, "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com", "subnet-a"),
+			},
+		}, nil)
+		mockKafka.On("GetBootstrapBrokers", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.GetBootstrapBrokersOutput{
+			BootstrapBrokerStringTls:     aws.String("b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9094"),
+			BootstrapBrokerStringSaslIam: aws.String("b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9098"),
+		}, nil)
+		mockEC2.On("DescribeSubnets", mock.Anything, mock.Anything, mock.Anything).Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"authentication": "sasl_iam"},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, 9098, result[0].Port)
+		assert.Equal(t, "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9098", result[0].Metadata["BootstrapBrokers"])
+	})
+
+	t.Run("cluster without the listener is skipped", func(t *testing.T) {
+		mockKafka := new(MockKafkaClient)
+		mockEC2 := new(MockEC2Client)
+
+		provider := newTestProvider(mockKafka, mockEC2)
+
+		mockKafka.On("ListClustersV2", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListClustersV2Output{
+			ClusterInfoList: []kafkatypes.Cluster{newCluster("events", testClusterARN, nil)},
+		}, nil)
+		mockKafka.On("ListNodes", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListNodesOutput{
+			NodeInfoList: []kafkatypes.NodeInfo{
+				newBrokerNode(1, "b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com", "subnet-a"),
+			},
+		}, nil)
+		mockKafka.On("GetBootstrapBrokers", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.GetBootstrapBrokersOutput{
+			BootstrapBrokerStringTls: aws.String("b-1.events.abcd12.c2.kafka.ap-northeast-1.amazonaws.com:9094"),
+		}, nil)
+		mockEC2.On("DescribeSubnets", mock.Anything, mock.Anything, mock.Anything).Return(&ec2.DescribeSubnetsOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"authentication": "plaintext"},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("no matching clusters", func(t *testing.T) {
+		mockKafka := new(MockKafkaClient)
+		mockEC2 := new(MockEC2Client)
+
+		provider := newTestProvider(mockKafka, mockEC2)
+
+		mockKafka.On("ListClustersV2", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListClustersV2Output{
+			ClusterInfoList: []kafkatypes.Cluster{newCluster("events", testClusterARN, map[string]string{"env": "staging"})},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockKafka.AssertNotCalled(t, "ListNodes", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("list nodes error", func(t *testing.T) {
+		mockKafka := new(MockKafkaClient)
+		mockEC2 := new(MockEC2Client)
+
+		provider := newTestProvider(mockKafka, mockEC2)
+
+		mockKafka.On("ListClustersV2", mock.Anything, mock.Anything, mock.Anything).Return(&kafka.ListClustersV2Output{
+			ClusterInfoList: []kafkatypes.Cluster{newCluster("events", testClusterARN, nil)},
+		}, nil)
+		mockKafka.On("ListNodes", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list nodes of "+testClusterARN)
+	})
+}
+
+func TestBootstrapPort(t *testing.T) {
+	port, err := bootstrapPort("b-1.example.com:9094,b-2.example.com:9094")
+	require.NoError(t, err)
+	assert.Equal(t, 9094, port)
+
+	_, err = bootstrapPort("b-1.example.com")
+	assert.Error(t, err)
+}
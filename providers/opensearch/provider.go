@@ -0,0 +1,291 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchtypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "opensearch_domain"
+
+// httpsPort is the port of domain endpoints, which only accept HTTPS
+const httpsPort = 443
+
+// vpcEndpointKey is the key of the VPC endpoint in DomainStatus.Endpoints
+const vpcEndpointKey = "vpc"
+
+const (
+	// describeConcurrency is the maximum number of describe and ListTags calls in flight
+	describeConcurrency = 5
+	// describeBatchSize is the maximum number of domains accepted by a DescribeDomains call
+	describeBatchSize = 5
+)
+
+// Provider implements the providers.Provider interface for Amazon OpenSearch Service
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	OpenSearch OpenSearchAPI
+}
+
+// OpenSearchAPI defines the OpenSearch Service API interface
+type OpenSearchAPI interface {
+	ListDomainNames(ctx context.Context, params *opensearch.ListDomainNamesInput, optFns ...func(*opensearch.Options)) (*opensearch.ListDomainNamesOutput, error)
+	DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error)
+	ListTags(ctx context.Context, params *opensearch.ListTagsInput, optFns ...func(*opensearch.Options)) (*opensearch.ListTagsOutput, error)
+}
+
+// NewProvider creates a new OpenSearch provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new OpenSearch provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		OpenSearch: opensearch.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves OpenSearch domains based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting OpenSearch discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	// Domains are listed and described first and filtered by tags afterwards
+	names, engineTypes, err := listDomainNames(ctx, clients.OpenSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		slog.Info("No OpenSearch domains found")
+		return []providers.Resource{}, nil
+	}
+
+	domains, err := describeDomains(ctx, clients.OpenSearch, names)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get tags of each domain; results keep the order of the domains
+	tagsPerDomain, err := providers.ParallelMap(ctx, domains, describeConcurrency, func(ctx context.Context, domain opensearchtypes.DomainStatus) (map[string]string, error) {
+		return listTags(ctx, clients.OpenSearch, aws.ToString(domain.ARN))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for i, domain := range domains {
+		name := aws.ToString(domain.DomainName)
+		if !awsutil.MatchTags(tagsPerDomain[i], tags) {
+			continue
+		}
+		if aws.ToBool(domain.Deleted) {
+			slog.Debug("Skipping deleted OpenSearch domain", "domain_name", name)
+			continue
+		}
+
+		resource, ok := newDomainResource(domain, engineTypes[name], tagsPerDomain[i])
+		if !ok {
+			slog.Warn("OpenSearch domain has no endpoint", "domain_name", name)
+			continue
+		}
+
+		slog.Debug("Extracted domain",
+			"host", resource.Host,
+			"port", resource.Port,
+			"domain_name", name)
+		result = append(result, resource)
+	}
+
+	slog.Info("OpenSearch discovery completed", "total_domains", len(result))
+	return result, nil
+}
+
+// listDomainNames returns the names of all domains in the region and the engine type of each
+func listDomainNames(ctx context.Context, client OpenSearchAPI) ([]string, map[string]string, error) {
+	// Catch panic and convert to error
+	var resp *opensearch.ListDomainNamesOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during ListDomainNames API call: %v", r)
+			}
+		}()
+		resp, err = client.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
+	}()
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list OpenSearch domains: %w", err)
+	}
+
+	names := make([]string, 0, len(resp.DomainNames))
+	engineTypes := make(map[string]string, len(resp.DomainNames))
+	for _, info := range resp.DomainNames {
+		name := aws.ToString(info.DomainName)
+		names = append(names, name)
+		engineTypes[name] = string(info.EngineType)
+	}
+	return names, engineTypes, nil
+}
+
+// describeDomains describes domains in batches of describeBatchSize, keeping the order of names
+func describeDomains(ctx context.Context, client OpenSearchAPI, names []string) ([]opensearchtypes.DomainStatus, error) {
+	var batches [][]string
+	for start := 0; start < len(names); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(names))
+		batches = append(batches, names[start:end])
+	}
+
+	domainsPerBatch, err := providers.ParallelMap(ctx, batches, describeConcurrency, func(ctx context.Context, batch []string) ([]opensearchtypes.DomainStatus, error) {
+		// Catch panic and convert to error
+		var resp *opensearch.DescribeDomainsOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeDomains API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{
+				DomainNames: batch,
+			})
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe OpenSearch domains: %w", err)
+		}
+		return resp.DomainStatusList, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]opensearchtypes.DomainStatus, len(names))
+	for _, domains := range domainsPerBatch {
+		for _, domain := range domains {
+			byName[aws.ToString(domain.DomainName)] = domain
+		}
+	}
+
+	var result []opensearchtypes.DomainStatus
+	for _, name := range names {
+		if domain, ok := byName[name]; ok {
+			result = append(result, domain)
+		}
+	}
+	return result, nil
+}
+
+// listTags returns the tags of an OpenSearch domain
+func listTags(ctx context.Context, client OpenSearchAPI, arn string) (map[string]string, error) {
+	// Catch panic and convert to error
+	var resp *opensearch.ListTagsOutput
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic occurred during ListTags API call: %v", r)
+			}
+		}()
+		resp, err = client.ListTags(ctx, &opensearch.ListTagsInput{
+			ARN: aws.String(arn),
+		})
+	}()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s: %w", arn, err)
+	}
+
+	tags := make(map[string]string, len(resp.TagList))
+	for _, tag := range resp.TagList {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
+		}
+	}
+	return tags, nil
+}
+
+// newDomainResource returns the resource for a domain. The public endpoint is used
+// as Host when the domain has one, otherwise the VPC endpoint.
+func newDomainResource(domain opensearchtypes.DomainStatus, engineType string, tags map[string]string) (providers.Resource, bool) {
+	endpoint := aws.ToString(domain.Endpoint)
+	vpcEndpoint := domain.Endpoints[vpcEndpointKey]
+
+	host := endpoint
+	if host == "" {
+		host = vpcEndpoint
+	}
+	if host == "" {
+		return providers.Resource{}, false
+	}
+
+	var fineGrainedAccessControl bool
+	if domain.AdvancedSecurityOptions != nil {
+		fineGrainedAccessControl = aws.ToBool(domain.AdvancedSecurityOptions.Enabled)
+	}
+
+	var customEndpoint string
+	if opts := domain.DomainEndpointOptions; opts != nil && aws.ToBool(opts.CustomEndpointEnabled) {
+		customEndpoint = aws.ToString(opts.CustomEndpoint)
+	}
+
+	return providers.Resource{
+		Host: host,
+		Port: httpsPort,
+		Tags: tags,
+		Metadata: map[string]interface{}{
+			"DomainName":               aws.ToString(domain.DomainName),
+			"ARN":                      aws.ToString(domain.ARN),
+			"URL":                      fmt.Sprintf("https://%s", host),
+			"Endpoint":                 endpoint,
+			"VPCEndpoint":              vpcEndpoint,
+			"CustomEndpoint":           customEndpoint,
+			"EngineType":               engineType,
+			"EngineVersion":            aws.ToString(domain.EngineVersion),
+			"FineGrainedAccessControl": fineGrainedAccessControl,
+		},
+	}, true
+}
@@ -0,0 +1,293 @@
+package opensearch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchtypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockOpenSearchClient is a mock implementation of OpenSearchAPI
+type MockOpenSearchClient struct {
+	mock.Mock
+}
+
+func (m *MockOpenSearchClient) ListDomainNames(ctx context.Context, params *opensearch.ListDomainNamesInput, optFns ...func(*opensearch.Options)) (*opensearch.ListDomainNamesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*opensearch.ListDomainNamesOutput), args.Error(1)
+}
+
+func (m *MockOpenSearchClient) DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*opensearch.DescribeDomainsOutput), args.Error(1)
+}
+
+func (m *MockOpenSearchClient) ListTags(ctx context.Context, params *opensearch.ListTagsInput, optFns ...func(*opensearch.Options)) (*opensearch.ListTagsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*opensearch.ListTagsOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client OpenSearchAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{OpenSearch: client}, nil
+	})
+}
+
+// domainARN returns the ARN of the domain with the given name
+func domainARN(name string) string {
+	return "arn:aws:es:ap-northeast-1:123456789012:domain/" + name
+}
+
+// newDomain returns a public domain with fine-grained access control enabled
+func newDomain(name string) opensearchtypes.DomainStatus {
+	return opensearchtypes.DomainStatus{
+		ARN:           aws.String(domainARN(name)),
+		DomainName:    aws.String(name),
+		Endpoint:      aws.String("search-" + name + "-abc123.ap-northeast-1.es.amazonaws.com"),
+		EngineVersion: aws.String("OpenSearch_2.13"),
+		AdvancedSecurityOptions: &opensearchtypes.AdvancedSecurityOptions{
+			Enabled: aws.Bool(true),
+		},
+	}
+}
+
+// newVPCDomain returns a domain that is only reachable through its VPC endpoint
+func newVPCDomain(name string) opensearchtypes.DomainStatus {
+	return opensearchtypes.DomainStatus{
+		ARN:           aws.String(domainARN(name)),
+		DomainName:    aws.String(name),
+		Endpoints:     map[string]string{"vpc": "vpc-" + name + "-abc123.ap-northeast-1.es.amazonaws.com"},
+		EngineVersion: aws.String("Elasticsearch_7.10"),
+	}
+}
+
+// listTagsOutput returns a ListTags response with the given key/value pairs
+func listTagsOutput(kv ...string) *opensearch.ListTagsOutput {
+	out := &opensearch.ListTagsOutput{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		out.TagList = append(out.TagList, opensearchtypes.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return out
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "opensearch_domain", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"tags": "invalid"},
+	})
+	assert.EqualError(t, err, "filters.tags must be a map")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		mockOpenSearch.On("ListDomainNames", mock.Anything, &opensearch.ListDomainNamesInput{}, mock.Anything).Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: []opensearchtypes.DomainInfo{
+				{DomainName: aws.String("logs"), EngineType: opensearchtypes.EngineTypeOpenSearch},
+				{DomainName: aws.String("search"), EngineType: opensearchtypes.EngineTypeElasticsearch},
+				{DomainName: aws.String("staging-logs"), EngineType: opensearchtypes.EngineTypeOpenSearch},
+			},
+		}, nil)
+
+		mockOpenSearch.On("DescribeDomains", mock.Anything, &opensearch.DescribeDomainsInput{
+			DomainNames: []string{"logs", "search", "staging-logs"},
+		}, mock.Anything).Return(&opensearch.DescribeDomainsOutput{
+			DomainStatusList: []opensearchtypes.DomainStatus{newVPCDomain("search"), newDomain("staging-logs"), newDomain("logs")},
+		}, nil)
+
+		mockOpenSearch.On("ListTags", mock.Anything, &opensearch.ListTagsInput{
+			ARN: aws.String(domainARN("logs")),
+		}, mock.Anything).Return(listTagsOutput("Environment", "production", "Team", "platform"), nil)
+		mockOpenSearch.On("ListTags", mock.Anything, &opensearch.ListTagsInput{
+			ARN: aws.String(domainARN("search")),
+		}, mock.Anything).Return(listTagsOutput("Environment", "production"), nil)
+		mockOpenSearch.On("ListTags", mock.Anything, &opensearch.ListTagsInput{
+			ARN: aws.String(domainARN("staging-logs")),
+		}, mock.Anything).Return(listTagsOutput("Environment", "staging"), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"Environment": "production"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "search-logs-abc123.ap-northeast-1.es.amazonaws.com", resource.Host)
+		assert.Equal(t, 443, resource.Port)
+		assert.Equal(t, "platform", resource.Tags["Team"])
+		assert.Equal(t, "logs", resource.Metadata["DomainName"])
+		assert.Equal(t, domainARN("logs"), resource.Metadata["ARN"])
+		assert.Equal(t, "https://search-logs-abc123.ap-northeast-1.es.amazonaws.com", resource.Metadata["URL"])
+		assert.Equal(t, "search-logs-abc123.ap-northeast-1.es.amazonaws.com", resource.Metadata["Endpoint"])
+		assert.Equal(t, "", resource.Metadata["VPCEndpoint"])
+		assert.Equal(t, "", resource.Metadata["CustomEndpoint"])
+		assert.Equal(t, "OpenSearch", resource.Metadata["EngineType"])
+		assert.Equal(t, "OpenSearch_2.13", resource.Metadata["EngineVersion"])
+		assert.Equal(t, true, resource.Metadata["FineGrainedAccessControl"])
+
+		resource = result[1]
+		assert.Equal(t, "vpc-search-abc123.ap-northeast-1.es.amazonaws.com", resource.Host)
+		assert.Equal(t, "https://vpc-search-abc123.ap-northeast-1.es.amazonaws.com", resource.Metadata["URL"])
+		assert.Equal(t, "", resource.Metadata["Endpoint"])
+		assert.Equal(t, "vpc-search-abc123.ap-northeast-1.es.amazonaws.com", resource.Metadata["VPCEndpoint"])
+		assert.Equal(t, "Elasticsearch", resource.Metadata["EngineType"])
+		assert.Equal(t, "Elasticsearch_7.10", resource.Metadata["EngineVersion"])
+		assert.Equal(t, false, resource.Metadata["FineGrainedAccessControl"])
+
+		mockOpenSearch.AssertExpectations(t)
+	})
+
+	t.Run("custom endpoint", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		domain := newDomain("logs")
+		domain.DomainEndpointOptions = &opensearchtypes.DomainEndpointOptions{
+			CustomEndpointEnabled: aws.Bool(true),
+			CustomEndpoint:        aws.String("logs.example.com"),
+		}
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: []opensearchtypes.DomainInfo{{DomainName: aws.String("logs")}},
+		}, nil)
+		mockOpenSearch.On("DescribeDomains", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.DescribeDomainsOutput{
+			DomainStatusList: []opensearchtypes.DomainStatus{domain},
+		}, nil)
+		mockOpenSearch.On("ListTags", mock.Anything, mock.Anything, mock.Anything).Return(listTagsOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "logs.example.com", result[0].Metadata["CustomEndpoint"])
+	})
+
+	t.Run("skips deleted domains and domains without endpoint", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		deleted := newDomain("deleted")
+		deleted.Deleted = aws.Bool(true)
+		creating := newDomain("creating")
+		creating.Endpoint = nil
+
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: []opensearchtypes.DomainInfo{{DomainName: aws.String("creating")}, {DomainName: aws.String("deleted")}},
+		}, nil)
+		mockOpenSearch.On("DescribeDomains", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.DescribeDomainsOutput{
+			DomainStatusList: []opensearchtypes.DomainStatus{creating, deleted},
+		}, nil)
+		mockOpenSearch.On("ListTags", mock.Anything, mock.Anything, mock.Anything).Return(listTagsOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("describes in batches", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		var infos []opensearchtypes.DomainInfo
+		for i := range 7 {
+			infos = append(infos, opensearchtypes.DomainInfo{DomainName: aws.String(fmt.Sprintf("domain-%d", i))})
+		}
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: infos,
+		}, nil)
+		describeOutput := func(names ...string) *opensearch.DescribeDomainsOutput {
+			out := &opensearch.DescribeDomainsOutput{}
+			for _, name := range names {
+				out.DomainStatusList = append(out.DomainStatusList, newDomain(name))
+			}
+			return out
+		}
+		mockOpenSearch.On("DescribeDomains", mock.Anything, &opensearch.DescribeDomainsInput{
+			DomainNames: []string{"domain-0", "domain-1", "domain-2", "domain-3", "domain-4"},
+		}, mock.Anything).Return(describeOutput("domain-0", "domain-1", "domain-2", "domain-3", "domain-4"), nil)
+		mockOpenSearch.On("DescribeDomains", mock.Anything, &opensearch.DescribeDomainsInput{
+			DomainNames: []string{"domain-5", "domain-6"},
+		}, mock.Anything).Return(describeOutput("domain-6", "domain-5"), nil)
+		mockOpenSearch.On("ListTags", mock.Anything, mock.Anything, mock.Anything).Return(listTagsOutput(), nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		require.Len(t, result, 7)
+		for i, resource := range result {
+			assert.Equal(t, fmt.Sprintf("domain-%d", i), resource.Metadata["DomainName"])
+		}
+		mockOpenSearch.AssertExpectations(t)
+	})
+
+	t.Run("no domains", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.ListDomainNamesOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockOpenSearch.AssertNotCalled(t, "DescribeDomains", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list OpenSearch domains")
+	})
+
+	t.Run("list tags error", func(t *testing.T) {
+		mockOpenSearch := new(MockOpenSearchClient)
+		provider := newTestProvider(mockOpenSearch)
+
+		mockOpenSearch.On("ListDomainNames", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: []opensearchtypes.DomainInfo{{DomainName: aws.String("logs")}},
+		}, nil)
+		mockOpenSearch.On("DescribeDomains", mock.Anything, mock.Anything, mock.Anything).Return(&opensearch.DescribeDomainsOutput{
+			DomainStatusList: []opensearchtypes.DomainStatus{newDomain("logs")},
+		}, nil)
+		mockOpenSearch.On("ListTags", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to list tags of "+domainARN("logs"))
+	})
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ParallelMap calls fn for each item with at most concurrency calls in flight
+// and returns the results in the same order as items.
+// The first error cancels the context passed to the remaining calls and is
+// returned; when several calls fail, the error of the earliest item wins so
+// the result is deterministic.
+func ParallelMap[T, R any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, item T) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := fn(ctx, item)
+			if err != nil {
+				errs[i] = err
+				cancel()
+				return
+			}
+			results[i] = result
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	// Prefer a real error over the cancellations it caused in other items
+	var canceledErr error
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+		if canceledErr == nil {
+			canceledErr = err
+		}
+	}
+	if canceledErr != nil {
+		return nil, canceledErr
+	}
+
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelMap(t *testing.T) {
+	t.Run("results keep input order", func(t *testing.T) {
+		items := []int{5, 4, 3, 2, 1}
+
+		results, err := ParallelMap(context.Background(), items, 3, func(ctx context.Context, item int) (string, error) {
+			// Finish later items first
+			time.Sleep(time.Duration(item) * time.Millisecond)
+			return fmt.Sprintf("item-%d", item), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"item-5", "item-4", "item-3", "item-2", "item-1"}, results)
+	})
+
+	t.Run("concurrency is bounded", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		items := make([]int, 20)
+
+		_, err := ParallelMap(context.Background(), items, 4, func(ctx context.Context, item int) (int, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return item, nil
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+	})
+
+	t.Run("zero concurrency runs sequentially", func(t *testing.T) {
+		results, err := ParallelMap(context.Background(), []int{1, 2}, 0, func(ctx context.Context, item int) (int, error) {
+			return item * 10, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{10, 20}, results)
+	})
+
+	t.Run("empty items", func(t *testing.T) {
+		results, err := ParallelMap(context.Background(), []int{}, 2, func(ctx context.Context, item int) (int, error) {
+			return item, nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("error cancels remaining calls", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		items := []int{0, 1, 2, 3}
+
+		_, err := ParallelMap(context.Background(), items, 4, func(ctx context.Context, item int) (int, error) {
+			if item == 2 {
+				return 0, errBoom
+			}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		assert.ErrorIs(t, err, errBoom)
+	})
+}
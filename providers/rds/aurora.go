@@ -0,0 +1,72 @@
+package rds
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const auroraProviderType = "rds_aurora"
+
+// defaultAuroraEngines are the engines discovered when filters.engines is not set
+var defaultAuroraEngines = []string{"aurora-mysql", "aurora-postgresql"}
+
+// AuroraProvider implements the providers.Provider interface for Aurora DB clusters
+type AuroraProvider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// NewAuroraProvider creates a new Aurora provider
+func NewAuroraProvider() *AuroraProvider {
+	return NewAuroraProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewAuroraProviderWithClientFactory creates a new Aurora provider that obtains
+// its AWS clients from the given factory
+func NewAuroraProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *AuroraProvider {
+	return &AuroraProvider{
+		newClients: newClients,
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *AuroraProvider) Type() string {
+	return auroraProviderType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *AuroraProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return validateConfig(cfg)
+}
+
+// Discover retrieves the member instances of Aurora DB clusters based on the configuration
+func (p *AuroraProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting Aurora discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	engines, _ := providers.StringListFilter(cfg.Filters, enginesFilter)
+	if len(engines) == 0 {
+		engines = defaultAuroraEngines
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags, "engines", engines)
+
+	result, err := discoverClusterMembers(ctx, clients, tags, engines)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("Aurora discovery completed", "total_instances", len(result))
+	return result, nil
+}
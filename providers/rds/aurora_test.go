@@ -0,0 +1,138 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuroraProvider_Type(t *testing.T) {
+	provider := NewAuroraProvider()
+	assert.Equal(t, "rds_aurora", provider.Type())
+}
+
+func TestAuroraProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewAuroraProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("rds:cluster"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				newDBClusterMapping("orders"),
+			},
+		}, nil)
+
+		mockRDS.On("DescribeDBClusters", mock.Anything, &rds.DescribeDBClustersInput{
+			Filters: []rdstypes.Filter{
+				{Name: aws.String("db-cluster-id"), Values: []string{"orders"}},
+				{Name: aws.String("engine"), Values: []string{"aurora-mysql", "aurora-postgresql"}},
+			},
+		}, mock.Anything).Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				newDBCluster("orders", "aurora-mysql", "orders-1", "orders-2", "orders-3"),
+			},
+		}, nil)
+
+		writer := newDBInstance("orders-1", "aurora-mysql")
+		writer.EngineVersion = aws.String("8.0.mysql_aurora.3.08.0")
+		writer.DBInstanceClass = aws.String("db.r7g.large")
+		writer.AvailabilityZone = aws.String("ap-northeast-1a")
+		writer.DbiResourceId = aws.String("db-WRITER")
+
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return assert.ObjectsAreEqual([]string{"orders-2", "orders-3", "orders-1"}, dbInstanceIDFilter(input))
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{
+				newDBInstance("orders-3", "aurora-mysql"),
+				writer,
+				// orders-2 is still being created
+				{DBInstanceIdentifier: aws.String("orders-2"), DBInstanceStatus: aws.String("creating")},
+			},
+		}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"env": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "orders-1.abc123.ap-northeast-1.rds.amazonaws.com", resource.Host)
+		assert.Equal(t, 3306, resource.Port)
+		assert.Equal(t, "prod", resource.Tags["env"])
+		assert.Equal(t, "orders", resource.Metadata["ClusterIdentifier"])
+		assert.Equal(t, true, resource.Metadata["IsWriter"])
+		assert.Equal(t, "orders.cluster-abc123.ap-northeast-1.rds.amazonaws.com", resource.Metadata["ClusterEndpoint"])
+		assert.Equal(t, "orders.cluster-ro-abc123.ap-northeast-1.rds.amazonaws.com", resource.Metadata["ReaderEndpoint"])
+		assert.Equal(t, "cluster-ABCDEFGHIJKL", resource.Metadata["DbClusterResourceId"])
+		assert.Equal(t, "orders-1", resource.Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, "aurora-mysql", resource.Metadata["Engine"])
+		assert.Equal(t, "8.0.mysql_aurora.3.08.0", resource.Metadata["EngineVersion"])
+		assert.Equal(t, "db.r7g.large", resource.Metadata["DBInstanceClass"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "db-WRITER", resource.Metadata["DbiResourceId"])
+
+		assert.Equal(t, "orders-3", result[1].Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, false, result[1].Metadata["IsWriter"])
+
+		mockTagging.AssertExpectations(t)
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("engines filter overrides the default engines", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewAuroraProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{newDBClusterMapping("orders")},
+		}, nil)
+		mockRDS.On("DescribeDBClusters", mock.Anything, &rds.DescribeDBClustersInput{
+			Filters: []rdstypes.Filter{
+				{Name: aws.String("db-cluster-id"), Values: []string{"orders"}},
+				{Name: aws.String("engine"), Values: []string{"aurora-postgresql"}},
+			},
+		}, mock.Anything).Return(&rds.DescribeDBClustersOutput{}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"engines": []interface{}{"aurora-postgresql"}},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockRDS.AssertExpectations(t)
+		mockRDS.AssertNotCalled(t, "DescribeDBInstances", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewAuroraProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockRDS.AssertNotCalled(t, "DescribeDBClusters", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,210 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+// discoverClusterMembers retrieves the member instances of the DB clusters that match
+// the tags and run one of the engines. Members are returned cluster by cluster in the
+// order of the tag results, writer first and then readers by identifier.
+func discoverClusterMembers(ctx context.Context, clients *Clients, tags map[string]string, engines []string) ([]providers.Resource, error) {
+	// Get DB clusters by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, dbClusterResourceType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourceTagMappings) == 0 {
+		slog.Info("No DB clusters found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found DB clusters by tags", "count", len(resourceTagMappings))
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	ids := make([]string, 0, len(resourceTagMappings))
+	idToTags := make(map[string]map[string]string, len(resourceTagMappings))
+	for _, mapping := range resourceTagMappings {
+		arn := aws.ToString(mapping.ResourceARN)
+		id := awsutil.ResourceIDFromARN(arn)
+		ids = append(ids, id)
+		idToTags[id] = arnToTags[arn]
+	}
+
+	// Describe DB clusters; results keep the order of the IDs
+	clusters, err := describeDBClusters(ctx, clients.RDS, ids, engines)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberIDs []string
+	for _, cluster := range clusters {
+		for _, member := range cluster.DBClusterMembers {
+			memberIDs = append(memberIDs, aws.ToString(member.DBInstanceIdentifier))
+		}
+	}
+
+	// Describe the member instances to get their endpoints
+	instances, err := describeDBInstances(ctx, clients.RDS, memberIDs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceByID := make(map[string]rdstypes.DBInstance, len(instances))
+	for _, instance := range instances {
+		instanceByID[aws.ToString(instance.DBInstanceIdentifier)] = instance
+	}
+
+	var result []providers.Resource
+	for _, cluster := range clusters {
+		clusterID := aws.ToString(cluster.DBClusterIdentifier)
+
+		members := sortedClusterMembers(cluster.DBClusterMembers)
+		for _, member := range members {
+			memberID := aws.ToString(member.DBInstanceIdentifier)
+			instance, ok := instanceByID[memberID]
+			if !ok || instance.Endpoint == nil {
+				slog.Warn("DB cluster member has no endpoint",
+					"db_cluster_identifier", clusterID,
+					"db_instance_identifier", memberID)
+				continue
+			}
+
+			resource := newClusterMemberResource(cluster, member, instance, idToTags[clusterID])
+			slog.Debug("Extracted DB cluster member",
+				"host", resource.Host,
+				"port", resource.Port,
+				"db_cluster_identifier", clusterID,
+				"db_instance_identifier", memberID,
+				"is_writer", aws.ToBool(member.IsClusterWriter))
+			result = append(result, resource)
+		}
+	}
+
+	return result, nil
+}
+
+// sortedClusterMembers returns the members of a cluster with the writer first
+// and the readers ordered by identifier
+func sortedClusterMembers(members []rdstypes.DBClusterMember) []rdstypes.DBClusterMember {
+	sorted := make([]rdstypes.DBClusterMember, len(members))
+	copy(sorted, members)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		wi, wj := aws.ToBool(sorted[i].IsClusterWriter), aws.ToBool(sorted[j].IsClusterWriter)
+		if wi != wj {
+			return wi
+		}
+		return aws.ToString(sorted[i].DBInstanceIdentifier) < aws.ToString(sorted[j].DBInstanceIdentifier)
+	})
+	return sorted
+}
+
+// newClusterMemberResource returns the resource for the instance endpoint of a cluster member
+func newClusterMemberResource(cluster rdstypes.DBCluster, member rdstypes.DBClusterMember, instance rdstypes.DBInstance, tags map[string]string) providers.Resource {
+	return providers.Resource{
+		Host: aws.ToString(instance.Endpoint.Address),
+		Port: int(aws.ToInt32(instance.Endpoint.Port)),
+		Tags: tags,
+		Metadata: map[string]interface{}{
+			"ClusterIdentifier":    aws.ToString(cluster.DBClusterIdentifier),
+			"IsWriter":             aws.ToBool(member.IsClusterWriter),
+			"ClusterEndpoint":      aws.ToString(cluster.Endpoint),
+			"ReaderEndpoint":       aws.ToString(cluster.ReaderEndpoint),
+			"DbClusterResourceId":  aws.ToString(cluster.DbClusterResourceId),
+			"DBInstanceIdentifier": aws.ToString(instance.DBInstanceIdentifier),
+			"Engine":               aws.ToString(instance.Engine),
+			"EngineVersion":        aws.ToString(instance.EngineVersion),
+			"DBInstanceClass":      aws.ToString(instance.DBInstanceClass),
+			"AvailabilityZone":     aws.ToString(instance.AvailabilityZone),
+			"DbiResourceId":        aws.ToString(instance.DbiResourceId),
+		},
+	}
+}
+
+// describeDBClusters returns the DB clusters with the given identifiers, in the
+// order of ids. Identifiers are looked up in batches with the db-cluster-id filter,
+// and clusters whose engine is not in engines are left out when engines is not empty.
+func describeDBClusters(ctx context.Context, client RDSAPI, ids []string, engines []string) ([]rdstypes.DBCluster, error) {
+	var batches [][]string
+	for start := 0; start < len(ids); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(ids))
+		batches = append(batches, ids[start:end])
+	}
+
+	clustersPerBatch, err := providers.ParallelMap(ctx, batches, describeConcurrency, func(ctx context.Context, batch []string) ([]rdstypes.DBCluster, error) {
+		filters := []rdstypes.Filter{
+			{Name: aws.String("db-cluster-id"), Values: batch},
+		}
+		if len(engines) > 0 {
+			filters = append(filters, rdstypes.Filter{Name: aws.String("engine"), Values: engines})
+		}
+		return listDBClusters(ctx, client, filters)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]rdstypes.DBCluster, len(ids))
+	for _, clusters := range clustersPerBatch {
+		for _, cluster := range clusters {
+			byID[aws.ToString(cluster.DBClusterIdentifier)] = cluster
+		}
+	}
+
+	var result []rdstypes.DBCluster
+	for _, id := range ids {
+		if cluster, ok := byID[id]; ok {
+			result = append(result, cluster)
+		}
+	}
+	return result, nil
+}
+
+// listDBClusters lists all DB clusters matching the filters, following Marker
+func listDBClusters(ctx context.Context, client RDSAPI, filters []rdstypes.Filter) ([]rdstypes.DBCluster, error) {
+	var result []rdstypes.DBCluster
+	var marker *string
+
+	for {
+		input := &rds.DescribeDBClustersInput{
+			Filters: filters,
+			Marker:  marker,
+		}
+
+		// Catch panic and convert to error
+		var resp *rds.DescribeDBClustersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeDBClusters API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeDBClusters(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB clusters: %w", err)
+		}
+		result = append(result, resp.DBClusters...)
+
+		slog.Debug("Retrieved DB clusters page",
+			"page_count", len(resp.DBClusters),
+			"total_count", len(result))
+
+		if aws.ToString(resp.Marker) == "" {
+			return result, nil
+		}
+		marker = resp.Marker
+	}
+}
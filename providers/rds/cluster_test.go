@@ -0,0 +1,93 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newDBClusterMapping returns a tag mapping for a DB cluster identifier
+func newDBClusterMapping(id string) taggingtypes.ResourceTagMapping {
+	return taggingtypes.ResourceTagMapping{
+		ResourceARN: aws.String("arn:aws:rds:ap-northeast-1:123456789012:cluster:" + id),
+		Tags:        []taggingtypes.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+	}
+}
+
+// newDBCluster returns a DB cluster with a writer and the given readers
+func newDBCluster(id, engine, writer string, readers ...string) rdstypes.DBCluster {
+	members := []rdstypes.DBClusterMember{}
+	for _, reader := range readers {
+		members = append(members, rdstypes.DBClusterMember{
+			DBInstanceIdentifier: aws.String(reader),
+			IsClusterWriter:      aws.Bool(false),
+		})
+	}
+	members = append(members, rdstypes.DBClusterMember{
+		DBInstanceIdentifier: aws.String(writer),
+		IsClusterWriter:      aws.Bool(true),
+	})
+
+	return rdstypes.DBCluster{
+		DBClusterIdentifier: aws.String(id),
+		DbClusterResourceId: aws.String("cluster-ABCDEFGHIJKL"),
+		Engine:              aws.String(engine),
+		Endpoint:            aws.String(id + ".cluster-abc123.ap-northeast-1.rds.amazonaws.com"),
+		ReaderEndpoint:      aws.String(id + ".cluster-ro-abc123.ap-northeast-1.rds.amazonaws.com"),
+		DBClusterMembers:    members,
+	}
+}
+
+func TestSortedClusterMembers(t *testing.T) {
+	members := newDBCluster("cluster", "aurora-mysql", "instance-2", "instance-3", "instance-1").DBClusterMembers
+
+	sorted := sortedClusterMembers(members)
+	require.Len(t, sorted, 3)
+	assert.Equal(t, "instance-2", aws.ToString(sorted[0].DBInstanceIdentifier))
+	assert.Equal(t, "instance-1", aws.ToString(sorted[1].DBInstanceIdentifier))
+	assert.Equal(t, "instance-3", aws.ToString(sorted[2].DBInstanceIdentifier))
+
+	// The input is left untouched
+	assert.Equal(t, "instance-3", aws.ToString(members[0].DBInstanceIdentifier))
+}
+
+func TestDescribeDBClusters(t *testing.T) {
+	t.Run("keeps the order of the IDs and follows Marker", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+
+		mockRDS.On("DescribeDBClusters", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBClustersInput) bool {
+			return input.Marker == nil
+		}), mock.Anything).Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{newDBCluster("cluster-b", "aurora-mysql", "b-1")},
+			Marker:     aws.String("next"),
+		}, nil).Once()
+		mockRDS.On("DescribeDBClusters", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBClustersInput) bool {
+			return aws.ToString(input.Marker) == "next"
+		}), mock.Anything).Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{newDBCluster("cluster-a", "aurora-mysql", "a-1")},
+		}, nil).Once()
+
+		clusters, err := describeDBClusters(context.Background(), mockRDS, []string{"cluster-a", "cluster-b"}, nil)
+		require.NoError(t, err)
+		require.Len(t, clusters, 2)
+		assert.Equal(t, "cluster-a", aws.ToString(clusters[0].DBClusterIdentifier))
+		assert.Equal(t, "cluster-b", aws.ToString(clusters[1].DBClusterIdentifier))
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+		mockRDS.On("DescribeDBClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := describeDBClusters(context.Background(), mockRDS, []string{"cluster-a"}, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe DB clusters")
+	})
+}
@@ -0,0 +1,69 @@
+package rds
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const docDBProviderType = "docdb_cluster"
+
+// docDBEngines are the engines of DocumentDB clusters in the RDS API
+var docDBEngines = []string{"docdb"}
+
+// DocDBProvider implements the providers.Provider interface for Amazon DocumentDB clusters
+type DocDBProvider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// NewDocDBProvider creates a new DocumentDB provider
+func NewDocDBProvider() *DocDBProvider {
+	return NewDocDBProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewDocDBProviderWithClientFactory creates a new DocumentDB provider that obtains
+// its AWS clients from the given factory
+func NewDocDBProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *DocDBProvider {
+	return &DocDBProvider{
+		newClients: newClients,
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *DocDBProvider) Type() string {
+	return docDBProviderType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *DocDBProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves the member instances of DocumentDB clusters based on the configuration
+func (p *DocDBProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting DocumentDB discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	// DocumentDB clusters are RDS DB clusters with the docdb engine
+	result, err := discoverClusterMembers(ctx, clients, tags, docDBEngines)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info("DocumentDB discovery completed", "total_instances", len(result))
+	return result, nil
+}
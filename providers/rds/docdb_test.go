@@ -0,0 +1,108 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocDBProvider_Type(t *testing.T) {
+	provider := NewDocDBProvider()
+	assert.Equal(t, "docdb_cluster", provider.Type())
+}
+
+func TestDocDBProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewDocDBProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("rds:cluster"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				newDBClusterMapping("catalog"),
+				newDBClusterMapping("orders"),
+			},
+		}, nil)
+
+		// orders is an Aurora cluster and is left out by the engine filter
+		catalog := newDBCluster("catalog", "docdb", "catalog-2", "catalog-1")
+		catalog.Endpoint = aws.String("catalog.cluster-abc123.ap-northeast-1.docdb.amazonaws.com")
+		catalog.ReaderEndpoint = aws.String("catalog.cluster-ro-abc123.ap-northeast-1.docdb.amazonaws.com")
+		mockRDS.On("DescribeDBClusters", mock.Anything, &rds.DescribeDBClustersInput{
+			Filters: []rdstypes.Filter{
+				{Name: aws.String("db-cluster-id"), Values: []string{"catalog", "orders"}},
+				{Name: aws.String("engine"), Values: []string{"docdb"}},
+			},
+		}, mock.Anything).Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{catalog},
+		}, nil)
+
+		newDocDBInstance := func(id string) rdstypes.DBInstance {
+			instance := newDBInstance(id, "docdb")
+			instance.Endpoint = &rdstypes.Endpoint{
+				Address: aws.String(id + ".abc123.ap-northeast-1.docdb.amazonaws.com"),
+				Port:    aws.Int32(27017),
+			}
+			instance.EngineVersion = aws.String("5.0.0")
+			return instance
+		}
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return assert.ObjectsAreEqual([]string{"catalog-1", "catalog-2"}, dbInstanceIDFilter(input))
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{newDocDBInstance("catalog-1"), newDocDBInstance("catalog-2")},
+		}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"env": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "catalog-2.abc123.ap-northeast-1.docdb.amazonaws.com", resource.Host)
+		assert.Equal(t, 27017, resource.Port)
+		assert.Equal(t, "prod", resource.Tags["env"])
+		assert.Equal(t, "catalog", resource.Metadata["ClusterIdentifier"])
+		assert.Equal(t, true, resource.Metadata["IsWriter"])
+		assert.Equal(t, "catalog.cluster-abc123.ap-northeast-1.docdb.amazonaws.com", resource.Metadata["ClusterEndpoint"])
+		assert.Equal(t, "catalog.cluster-ro-abc123.ap-northeast-1.docdb.amazonaws.com", resource.Metadata["ReaderEndpoint"])
+		assert.Equal(t, "catalog-2", resource.Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, "docdb", resource.Metadata["Engine"])
+		assert.Equal(t, "5.0.0", resource.Metadata["EngineVersion"])
+
+		assert.Equal(t, "catalog-1", result[1].Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, false, result[1].Metadata["IsWriter"])
+
+		mockTagging.AssertExpectations(t)
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewDocDBProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockRDS.AssertNotCalled(t, "DescribeDBClusters", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,151 @@
+package rds
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const instanceProviderType = "rds_instance"
+
+// includeClusterMembersFilter enables discovery of DB instances that belong to a DB cluster
+const includeClusterMembersFilter = "include_cluster_members"
+
+// InstanceProvider implements the providers.Provider interface for RDS DB instances
+type InstanceProvider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// NewInstanceProvider creates a new RDS DB instance provider
+func NewInstanceProvider() *InstanceProvider {
+	return NewInstanceProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewInstanceProviderWithClientFactory creates a new RDS DB instance provider that
+// obtains its AWS clients from the given factory
+func NewInstanceProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *InstanceProvider {
+	return &InstanceProvider{
+		newClients: newClients,
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *InstanceProvider) Type() string {
+	return instanceProviderType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *InstanceProvider) ValidateConfig(cfg providers.ProviderConfig) error {
+	if err := validateConfig(cfg); err != nil {
+		return err
+	}
+
+	_, err := providers.BoolFilter(cfg.Filters, includeClusterMembersFilter)
+	return err
+}
+
+// Discover retrieves RDS DB instances based on the configuration
+func (p *InstanceProvider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting RDS instance discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+	engines, _ := providers.StringListFilter(cfg.Filters, enginesFilter)
+	includeClusterMembers, _ := providers.BoolFilter(cfg.Filters, includeClusterMembersFilter)
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags, "engines", engines)
+
+	// Get DB instances by tags
+	resourceTagMappings, err := awsutil.GetResourcesByTags(ctx, clients.Tagging, dbInstanceResourceType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resourceTagMappings) == 0 {
+		slog.Info("No DB instances found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Found DB instances by tags", "count", len(resourceTagMappings))
+
+	arnToTags := awsutil.BuildARNToTagsMap(resourceTagMappings)
+
+	ids := make([]string, 0, len(resourceTagMappings))
+	idToTags := make(map[string]map[string]string, len(resourceTagMappings))
+	for _, mapping := range resourceTagMappings {
+		arn := aws.ToString(mapping.ResourceARN)
+		id := awsutil.ResourceIDFromARN(arn)
+		ids = append(ids, id)
+		idToTags[id] = arnToTags[arn]
+	}
+
+	// Describe DB instances; results keep the order of the IDs
+	instances, err := describeDBInstances(ctx, clients.RDS, ids, engines)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for _, instance := range instances {
+		id := aws.ToString(instance.DBInstanceIdentifier)
+		clusterID := aws.ToString(instance.DBClusterIdentifier)
+		if clusterID != "" && !includeClusterMembers {
+			slog.Debug("Skipping DB cluster member",
+				"db_instance_identifier", id,
+				"db_cluster_identifier", clusterID)
+			continue
+		}
+		if instance.Endpoint == nil {
+			slog.Warn("DB instance has no endpoint",
+				"db_instance_identifier", id,
+				"status", aws.ToString(instance.DBInstanceStatus))
+			continue
+		}
+
+		resource := newDBInstanceResource(instance, idToTags[id])
+		slog.Debug("Extracted DB instance",
+			"host", resource.Host,
+			"port", resource.Port,
+			"db_instance_identifier", id,
+			"engine", aws.ToString(instance.Engine))
+		result = append(result, resource)
+	}
+
+	slog.Info("RDS instance discovery completed", "total_instances", len(result))
+	return result, nil
+}
+
+// newDBInstanceResource returns the resource for the endpoint of a DB instance
+func newDBInstanceResource(instance rdstypes.DBInstance, tags map[string]string) providers.Resource {
+	readReplicaSource := aws.ToString(instance.ReadReplicaSourceDBInstanceIdentifier)
+
+	return providers.Resource{
+		Host: aws.ToString(instance.Endpoint.Address),
+		Port: int(aws.ToInt32(instance.Endpoint.Port)),
+		Tags: tags,
+		Metadata: map[string]interface{}{
+			"DBInstanceIdentifier": aws.ToString(instance.DBInstanceIdentifier),
+			"DBName":               aws.ToString(instance.DBName),
+			"Engine":               aws.ToString(instance.Engine),
+			"EngineVersion":        aws.ToString(instance.EngineVersion),
+			"DBInstanceClass":      aws.ToString(instance.DBInstanceClass),
+			"AvailabilityZone":     aws.ToString(instance.AvailabilityZone),
+			"DbiResourceId":        aws.ToString(instance.DbiResourceId),
+			"DBClusterIdentifier":  aws.ToString(instance.DBClusterIdentifier),
+			"ReadReplicaSource":    readReplicaSource,
+			"IsReadReplica":        readReplicaSource != "",
+		},
+	}
+}
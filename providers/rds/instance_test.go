@@ -0,0 +1,200 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceProvider_Type(t *testing.T) {
+	provider := NewInstanceProvider()
+	assert.Equal(t, "rds_instance", provider.Type())
+}
+
+func TestInstanceProvider_ValidateConfig(t *testing.T) {
+	provider := NewInstanceProvider()
+
+	t.Run("valid config", func(t *testing.T) {
+		err := provider.ValidateConfig(providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"engines": []interface{}{"mysql", "postgres"}},
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing region", func(t *testing.T) {
+		err := provider.ValidateConfig(providers.ProviderConfig{})
+		assert.EqualError(t, err, "region is required")
+	})
+
+	t.Run("invalid include_cluster_members filter", func(t *testing.T) {
+		err := provider.ValidateConfig(providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"include_cluster_members": "yes"},
+		})
+		assert.EqualError(t, err, "filters.include_cluster_members must be a boolean")
+	})
+
+	t.Run("invalid engines filter", func(t *testing.T) {
+		err := provider.ValidateConfig(providers.ProviderConfig{
+			Region:  "ap-northeast-1",
+			Filters: map[string]interface{}{"engines": "mysql"},
+		})
+		assert.EqualError(t, err, "filters.engines must be a list of strings")
+	})
+}
+
+func TestInstanceProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewInstanceProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, resourceTypeFilter("rds:db"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+			ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+				newDBInstanceMapping("primary-db"),
+				newDBInstanceMapping("replica-db"),
+				newDBInstanceMapping("creating-db"),
+			},
+		}, nil)
+
+		primary := newDBInstance("primary-db", "mysql")
+		primary.DBName = aws.String("app")
+		primary.EngineVersion = aws.String("8.0.39")
+		primary.DBInstanceClass = aws.String("db.r6g.large")
+		primary.AvailabilityZone = aws.String("ap-northeast-1a")
+		primary.DbiResourceId = aws.String("db-ABCDEFGHIJKL")
+
+		replica := newDBInstance("replica-db", "mysql")
+		replica.ReadReplicaSourceDBInstanceIdentifier = aws.String("primary-db")
+
+		creating := rdstypes.DBInstance{
+			DBInstanceIdentifier: aws.String("creating-db"),
+			DBInstanceStatus:     aws.String("creating"),
+		}
+
+		mockRDS.On("DescribeDBInstances", mock.Anything, &rds.DescribeDBInstancesInput{
+			Filters: []rdstypes.Filter{
+				{Name: aws.String("db-instance-id"), Values: []string{"primary-db", "replica-db", "creating-db"}},
+				{Name: aws.String("engine"), Values: []string{"mysql"}},
+			},
+		}, mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{creating, replica, primary},
+		}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags":    map[string]interface{}{"env": "prod"},
+				"engines": []interface{}{"mysql"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "primary-db.abc123.ap-northeast-1.rds.amazonaws.com", resource.Host)
+		assert.Equal(t, 3306, resource.Port)
+		assert.Equal(t, "prod", resource.Tags["env"])
+		assert.Equal(t, "primary-db", resource.Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, "app", resource.Metadata["DBName"])
+		assert.Equal(t, "mysql", resource.Metadata["Engine"])
+		assert.Equal(t, "8.0.39", resource.Metadata["EngineVersion"])
+		assert.Equal(t, "db.r6g.large", resource.Metadata["DBInstanceClass"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "db-ABCDEFGHIJKL", resource.Metadata["DbiResourceId"])
+		assert.Equal(t, "", resource.Metadata["DBClusterIdentifier"])
+		assert.Equal(t, "", resource.Metadata["ReadReplicaSource"])
+		assert.Equal(t, false, resource.Metadata["IsReadReplica"])
+
+		assert.Equal(t, "replica-db", result[1].Metadata["DBInstanceIdentifier"])
+		assert.Equal(t, "primary-db", result[1].Metadata["ReadReplicaSource"])
+		assert.Equal(t, true, result[1].Metadata["IsReadReplica"])
+
+		mockTagging.AssertExpectations(t)
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("cluster members", func(t *testing.T) {
+		for _, tc := range []struct {
+			name    string
+			filters map[string]interface{}
+			want    []string
+		}{
+			{name: "excluded by default", filters: map[string]interface{}{}, want: []string{"standalone-db"}},
+			{name: "included when enabled", filters: map[string]interface{}{"include_cluster_members": true}, want: []string{"aurora-1", "standalone-db"}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				mockTagging := new(MockResourceGroupsTaggingClient)
+				mockRDS := new(MockRDSClient)
+				ctx := context.Background()
+
+				provider := NewInstanceProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+				mockTagging.On("GetResources", ctx, resourceTypeFilter("rds:db"), mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{
+					ResourceTagMappingList: []taggingtypes.ResourceTagMapping{
+						newDBInstanceMapping("aurora-1"),
+						newDBInstanceMapping("standalone-db"),
+					},
+				}, nil)
+
+				member := newDBInstance("aurora-1", "aurora-mysql")
+				member.DBClusterIdentifier = aws.String("aurora-cluster")
+
+				mockRDS.On("DescribeDBInstances", mock.Anything, mock.Anything, mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+					DBInstances: []rdstypes.DBInstance{member, newDBInstance("standalone-db", "mysql")},
+				}, nil)
+
+				result, err := provider.Discover(ctx, providers.ProviderConfig{
+					Region:  "ap-northeast-1",
+					Filters: tc.filters,
+				})
+				require.NoError(t, err)
+
+				var ids []string
+				for _, resource := range result {
+					ids = append(ids, resource.Metadata["DBInstanceIdentifier"].(string))
+				}
+				assert.Equal(t, tc.want, ids)
+				if len(result) > 1 {
+					assert.Equal(t, "aurora-cluster", result[0].Metadata["DBClusterIdentifier"])
+				}
+			})
+		}
+	})
+
+	t.Run("no matching resources", func(t *testing.T) {
+		mockTagging := new(MockResourceGroupsTaggingClient)
+		mockRDS := new(MockRDSClient)
+		ctx := context.Background()
+
+		provider := NewInstanceProviderWithClientFactory(newTestClientFactory(mockTagging, mockRDS))
+
+		mockTagging.On("GetResources", ctx, mock.Anything, mock.Anything).Return(&resourcegroupstaggingapi.GetResourcesOutput{}, nil)
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.Empty(t, result)
+		mockRDS.AssertNotCalled(t, "DescribeDBInstances", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("client factory error", func(t *testing.T) {
+		provider := NewInstanceProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+			return nil, assert.AnError
+		})
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,138 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+// dbInstanceResourceType is the Resource Groups Tagging API type of DB instances
+const dbInstanceResourceType = "rds:db"
+
+// dbClusterResourceType is the Resource Groups Tagging API type of DB clusters
+const dbClusterResourceType = "rds:cluster"
+
+// enginesFilter restricts discovery to the listed database engines
+const enginesFilter = "engines"
+
+const (
+	// describeConcurrency is the maximum number of describe calls in flight
+	describeConcurrency = 5
+	// describeBatchSize is the maximum number of identifiers passed in a single describe filter
+	describeBatchSize = 100
+)
+
+// Clients holds the AWS clients used by the RDS providers
+type Clients struct {
+	RDS     RDSAPI
+	Tagging awsutil.ResourceGroupsTaggingAPI
+}
+
+// RDSAPI defines the RDS API interface
+type RDSAPI interface {
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+}
+
+// clientsFromConfig creates the AWS clients used by the RDS providers
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		RDS:     rds.NewFromConfig(awsCfg),
+		Tagging: resourcegroupstaggingapi.NewFromConfig(awsCfg),
+	}
+}
+
+// validateConfig checks the configuration common to the RDS providers
+func validateConfig(cfg providers.ProviderConfig) error {
+	if err := awsutil.ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	_, err := providers.StringListFilter(cfg.Filters, enginesFilter)
+	return err
+}
+
+// describeDBInstances returns the DB instances with the given identifiers, in the
+// order of ids. Identifiers are looked up in batches with the db-instance-id filter,
+// and instances whose engine is not in engines are left out when engines is not empty.
+func describeDBInstances(ctx context.Context, client RDSAPI, ids []string, engines []string) ([]rdstypes.DBInstance, error) {
+	var batches [][]string
+	for start := 0; start < len(ids); start += describeBatchSize {
+		end := min(start+describeBatchSize, len(ids))
+		batches = append(batches, ids[start:end])
+	}
+
+	instancesPerBatch, err := providers.ParallelMap(ctx, batches, describeConcurrency, func(ctx context.Context, batch []string) ([]rdstypes.DBInstance, error) {
+		filters := []rdstypes.Filter{
+			{Name: aws.String("db-instance-id"), Values: batch},
+		}
+		if len(engines) > 0 {
+			filters = append(filters, rdstypes.Filter{Name: aws.String("engine"), Values: engines})
+		}
+		return listDBInstances(ctx, client, filters)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]rdstypes.DBInstance, len(ids))
+	for _, instances := range instancesPerBatch {
+		for _, instance := range instances {
+			byID[aws.ToString(instance.DBInstanceIdentifier)] = instance
+		}
+	}
+
+	var result []rdstypes.DBInstance
+	for _, id := range ids {
+		if instance, ok := byID[id]; ok {
+			result = append(result, instance)
+		}
+	}
+	return result, nil
+}
+
+// listDBInstances lists all DB instances matching the filters, following Marker
+func listDBInstances(ctx context.Context, client RDSAPI, filters []rdstypes.Filter) ([]rdstypes.DBInstance, error) {
+	var result []rdstypes.DBInstance
+	var marker *string
+
+	for {
+		input := &rds.DescribeDBInstancesInput{
+			Filters: filters,
+			Marker:  marker,
+		}
+
+		// Catch panic and convert to error
+		var resp *rds.DescribeDBInstancesOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeDBInstances API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeDBInstances(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DB instances: %w", err)
+		}
+		result = append(result, resp.DBInstances...)
+
+		slog.Debug("Retrieved DB instances page",
+			"page_count", len(resp.DBInstances),
+			"total_count", len(result))
+
+		if aws.ToString(resp.Marker) == "" {
+			return result, nil
+		}
+		marker = resp.Marker
+	}
+}
@@ -0,0 +1,168 @@
+package rds
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi"
+	taggingtypes "github.com/aws/aws-sdk-go-v2/service/resourcegroupstaggingapi/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRDSClient is a mock implementation of RDSAPI
+type MockRDSClient struct {
+	mock.Mock
+}
+
+func (m *MockRDSClient) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*rds.DescribeDBInstancesOutput), args.Error(1)
+}
+
+func (m *MockRDSClient) DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*rds.DescribeDBClustersOutput), args.Error(1)
+}
+
+// MockResourceGroupsTaggingClient is a mock implementation of awsutil.ResourceGroupsTaggingAPI
+type MockResourceGroupsTaggingClient struct {
+	mock.Mock
+}
+
+func (m *MockResourceGroupsTaggingClient) GetResources(ctx context.Context, params *resourcegroupstaggingapi.GetResourcesInput, optFns ...func(*resourcegroupstaggingapi.Options)) (*resourcegroupstaggingapi.GetResourcesOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*resourcegroupstaggingapi.GetResourcesOutput), args.Error(1)
+}
+
+// newTestClientFactory returns a client factory that always returns the given mocks
+func newTestClientFactory(tagging *MockResourceGroupsTaggingClient, rdsClient *MockRDSClient) awsutil.ClientFactory[*Clients] {
+	return func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{
+			RDS:     rdsClient,
+			Tagging: tagging,
+		}, nil
+	}
+}
+
+// resourceTypeFilter matches GetResources calls for a single resource type
+func resourceTypeFilter(resourceType string) interface{} {
+	return mock.MatchedBy(func(input *resourcegroupstaggingapi.GetResourcesInput) bool {
+		return len(input.ResourceTypeFilters) == 1 && input.ResourceTypeFilters[0] == resourceType
+	})
+}
+
+// newDBInstanceMapping returns a tag mapping for a DB instance identifier
+func newDBInstanceMapping(id string) taggingtypes.ResourceTagMapping {
+	return taggingtypes.ResourceTagMapping{
+		ResourceARN: aws.String("arn:aws:rds:ap-northeast-1:123456789012:db:" + id),
+		Tags:        []taggingtypes.Tag{{Key: aws.String("env"), Value: aws.String("prod")}},
+	}
+}
+
+// newDBInstance returns an available DB instance with an endpoint
+func newDBInstance(id, engine string) rdstypes.DBInstance {
+	return rdstypes.DBInstance{
+		DBInstanceIdentifier: aws.String(id),
+		Engine:               aws.String(engine),
+		DBInstanceStatus:     aws.String("available"),
+		Endpoint: &rdstypes.Endpoint{
+			Address: aws.String(id + ".abc123.ap-northeast-1.rds.amazonaws.com"),
+			Port:    aws.Int32(3306),
+		},
+	}
+}
+
+// dbInstanceIDFilter returns the values of the db-instance-id filter of a describe call
+func dbInstanceIDFilter(input *rds.DescribeDBInstancesInput) []string {
+	for _, f := range input.Filters {
+		if aws.ToString(f.Name) == "db-instance-id" {
+			return f.Values
+		}
+	}
+	return nil
+}
+
+func TestDescribeDBInstances(t *testing.T) {
+	t.Run("keeps the order of the IDs and follows Marker", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return input.Marker == nil
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{newDBInstance("db-b", "mysql")},
+			Marker:      aws.String("next"),
+		}, nil).Once()
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return aws.ToString(input.Marker) == "next"
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{newDBInstance("db-a", "mysql")},
+		}, nil).Once()
+
+		instances, err := describeDBInstances(context.Background(), mockRDS, []string{"db-a", "db-b", "db-missing"}, nil)
+		require.NoError(t, err)
+		require.Len(t, instances, 2)
+		assert.Equal(t, "db-a", aws.ToString(instances[0].DBInstanceIdentifier))
+		assert.Equal(t, "db-b", aws.ToString(instances[1].DBInstanceIdentifier))
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("identifiers are split into batches", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+
+		var ids []string
+		for i := 0; i < describeBatchSize+1; i++ {
+			ids = append(ids, fmt.Sprintf("db-%03d", i))
+		}
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return len(dbInstanceIDFilter(input)) == describeBatchSize
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{}, nil).Once()
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.MatchedBy(func(input *rds.DescribeDBInstancesInput) bool {
+			return len(dbInstanceIDFilter(input)) == 1
+		}), mock.Anything).Return(&rds.DescribeDBInstancesOutput{}, nil).Once()
+
+		_, err := describeDBInstances(context.Background(), mockRDS, ids, nil)
+		require.NoError(t, err)
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("engine filter is passed to the API", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+
+		mockRDS.On("DescribeDBInstances", mock.Anything, &rds.DescribeDBInstancesInput{
+			Filters: []rdstypes.Filter{
+				{Name: aws.String("db-instance-id"), Values: []string{"db-a"}},
+				{Name: aws.String("engine"), Values: []string{"postgres"}},
+			},
+		}, mock.Anything).Return(&rds.DescribeDBInstancesOutput{}, nil)
+
+		_, err := describeDBInstances(context.Background(), mockRDS, []string{"db-a"}, []string{"postgres"})
+		require.NoError(t, err)
+		mockRDS.AssertExpectations(t)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mockRDS := new(MockRDSClient)
+		mockRDS.On("DescribeDBInstances", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := describeDBInstances(context.Background(), mockRDS, []string{"db-a"}, nil)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe DB instances")
+	})
+}
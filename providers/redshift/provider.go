@@ -0,0 +1,201 @@
+package redshift
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/providers/awsutil"
+)
+
+const providerType = "redshift_cluster"
+
+// describePageSize is the MaxRecords value used when listing clusters
+const describePageSize = 100
+
+// Provider implements the providers.Provider interface for Redshift clusters
+type Provider struct {
+	newClients awsutil.ClientFactory[*Clients]
+}
+
+// Clients holds the AWS clients used by the provider
+type Clients struct {
+	Redshift RedshiftAPI
+}
+
+// RedshiftAPI defines the Redshift API interface
+type RedshiftAPI interface {
+	DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error)
+}
+
+// NewProvider creates a new Redshift provider
+func NewProvider() *Provider {
+	return NewProviderWithClientFactory(awsutil.NewClientFactory(clientsFromConfig))
+}
+
+// NewProviderWithClientFactory creates a new Redshift provider that obtains
+// its AWS clients from the given factory
+func NewProviderWithClientFactory(newClients awsutil.ClientFactory[*Clients]) *Provider {
+	return &Provider{
+		newClients: newClients,
+	}
+}
+
+// clientsFromConfig creates the AWS clients used by the provider
+func clientsFromConfig(awsCfg aws.Config) *Clients {
+	return &Clients{
+		Redshift: redshift.NewFromConfig(awsCfg),
+	}
+}
+
+// Type returns the resource type handled by this provider
+func (p *Provider) Type() string {
+	return providerType
+}
+
+// ValidateConfig checks if the provider configuration is valid
+func (p *Provider) ValidateConfig(cfg providers.ProviderConfig) error {
+	return awsutil.ValidateConfig(cfg)
+}
+
+// Discover retrieves Redshift cluster leader endpoints based on the configuration
+func (p *Provider) Discover(ctx context.Context, cfg providers.ProviderConfig) ([]providers.Resource, error) {
+	slog.Debug("Starting Redshift discovery", "region", cfg.Region, "profile", cfg.Profile, "role_arn", cfg.RoleARN)
+
+	if err := p.ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	clients, err := p.newClients(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract tag filters from config
+	tags := awsutil.ExtractTagFilters(cfg.Filters)
+	slog.Debug("Extracted tag filters", "tag_count", len(tags), "tags", tags)
+
+	clusters, err := describeClusters(ctx, clients.Redshift, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []providers.Resource
+	for _, cluster := range clusters {
+		clusterID := aws.ToString(cluster.ClusterIdentifier)
+
+		// The tag keys and values of DescribeClusters match any of the given
+		// tags, so every tag is checked again here
+		clusterTags := tagsToMap(cluster.Tags)
+		if !awsutil.MatchTags(clusterTags, tags) {
+			continue
+		}
+
+		// Clusters being created have no endpoint yet
+		if cluster.Endpoint == nil || aws.ToString(cluster.Endpoint.Address) == "" {
+			slog.Debug("Skipping Redshift cluster without endpoint",
+				"cluster_identifier", clusterID,
+				"cluster_status", aws.ToString(cluster.ClusterStatus))
+			continue
+		}
+
+		host := aws.ToString(cluster.Endpoint.Address)
+		port := int(aws.ToInt32(cluster.Endpoint.Port))
+
+		slog.Debug("Extracted cluster endpoint",
+			"host", host,
+			"port", port,
+			"cluster_identifier", clusterID)
+
+		result = append(result, providers.Resource{
+			Host: host,
+			Port: port,
+			Tags: clusterTags,
+			Metadata: map[string]interface{}{
+				"ClusterIdentifier": clusterID,
+				"DBName":            aws.ToString(cluster.DBName),
+				"NodeType":          aws.ToString(cluster.NodeType),
+				"NumberOfNodes":     int(aws.ToInt32(cluster.NumberOfNodes)),
+				"Encrypted":         aws.ToBool(cluster.Encrypted),
+				"ClusterStatus":     aws.ToString(cluster.ClusterStatus),
+				"ClusterVersion":    aws.ToString(cluster.ClusterVersion),
+				"AvailabilityZone":  aws.ToString(cluster.AvailabilityZone),
+				"VpcID":             aws.ToString(cluster.VpcId),
+			},
+		})
+	}
+
+	if len(result) == 0 {
+		slog.Info("No Redshift clusters found matching tag filters", "tags", tags)
+		return []providers.Resource{}, nil
+	}
+
+	slog.Info("Redshift discovery completed", "total_clusters", len(result))
+	return result, nil
+}
+
+// describeClusters lists all clusters with any of the tag keys and values, following Marker.
+// Tag keys and values are sorted so that requests are deterministic.
+func describeClusters(ctx context.Context, client RedshiftAPI, tags map[string]string) ([]redshifttypes.Cluster, error) {
+	var tagKeys, tagValues []string
+	seenValues := make(map[string]bool)
+	for key, value := range tags {
+		tagKeys = append(tagKeys, key)
+		if !seenValues[value] {
+			seenValues[value] = true
+			tagValues = append(tagValues, value)
+		}
+	}
+	sort.Strings(tagKeys)
+	sort.Strings(tagValues)
+
+	var result []redshifttypes.Cluster
+	var marker *string
+
+	for {
+		input := &redshift.DescribeClustersInput{
+			MaxRecords: aws.Int32(describePageSize),
+			Marker:     marker,
+			TagKeys:    tagKeys,
+			TagValues:  tagValues,
+		}
+
+		// Catch panic and convert to error
+		var resp *redshift.DescribeClustersOutput
+		var err error
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("panic occurred during DescribeClusters API call: %v", r)
+				}
+			}()
+			resp, err = client.DescribeClusters(ctx, input)
+		}()
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe Redshift clusters: %w", err)
+		}
+		result = append(result, resp.Clusters...)
+
+		if aws.ToString(resp.Marker) == "" {
+			return result, nil
+		}
+		marker = resp.Marker
+	}
+}
+
+// tagsToMap converts Redshift tags to a map
+func tagsToMap(tags []redshifttypes.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		if tag.Key != nil && tag.Value != nil {
+			result[*tag.Key] = *tag.Value
+		}
+	}
+	return result
+}
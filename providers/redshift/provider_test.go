@@ -0,0 +1,188 @@
+package redshift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRedshiftClient is a mock implementation of RedshiftAPI
+type MockRedshiftClient struct {
+	mock.Mock
+}
+
+func (m *MockRedshiftClient) DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*redshift.DescribeClustersOutput), args.Error(1)
+}
+
+// newTestProvider creates a provider whose client factory always returns the given mock
+func newTestProvider(client RedshiftAPI) *Provider {
+	return NewProviderWithClientFactory(func(ctx context.Context, cfg providers.ProviderConfig) (*Clients, error) {
+		return &Clients{Redshift: client}, nil
+	})
+}
+
+// newCluster returns an available encrypted cluster with the given tags
+func newCluster(id string, kv ...string) redshifttypes.Cluster {
+	cluster := redshifttypes.Cluster{
+		ClusterIdentifier: aws.String(id),
+		ClusterStatus:     aws.String("available"),
+		ClusterVersion:    aws.String("1.0"),
+		DBName:            aws.String("dev"),
+		NodeType:          aws.String("ra3.xlplus"),
+		NumberOfNodes:     aws.Int32(2),
+		Encrypted:         aws.Bool(true),
+		AvailabilityZone:  aws.String("ap-northeast-1a"),
+		VpcId:             aws.String("vpc-0123"),
+		Endpoint: &redshifttypes.Endpoint{
+			Address: aws.String(id + ".abc123.ap-northeast-1.redshift.amazonaws.com"),
+			Port:    aws.Int32(5439),
+		},
+	}
+	for i := 0; i+1 < len(kv); i += 2 {
+		cluster.Tags = append(cluster.Tags, redshifttypes.Tag{Key: aws.String(kv[i]), Value: aws.String(kv[i+1])})
+	}
+	return cluster
+}
+
+func TestProvider_Type(t *testing.T) {
+	provider := NewProvider()
+	assert.Equal(t, "redshift_cluster", provider.Type())
+}
+
+func TestProvider_ValidateConfig(t *testing.T) {
+	provider := NewProvider()
+
+	assert.NoError(t, provider.ValidateConfig(providers.ProviderConfig{Region: "ap-northeast-1"}))
+
+	err := provider.ValidateConfig(providers.ProviderConfig{})
+	assert.EqualError(t, err, "region is required")
+
+	err = provider.ValidateConfig(providers.ProviderConfig{
+		Region:  "ap-northeast-1",
+		Filters: map[string]interface{}{"tags": "invalid"},
+	})
+	assert.EqualError(t, err, "filters.tags must be a map")
+}
+
+func TestProvider_Discover(t *testing.T) {
+	t.Run("successful discovery", func(t *testing.T) {
+		mockRedshift := new(MockRedshiftClient)
+		ctx := context.Background()
+
+		provider := newTestProvider(mockRedshift)
+
+		mockRedshift.On("DescribeClusters", mock.Anything, &redshift.DescribeClustersInput{
+			MaxRecords: aws.Int32(100),
+			TagKeys:    []string{"Environment", "Team"},
+			TagValues:  []string{"analytics", "production"},
+		}, mock.Anything).Return(&redshift.DescribeClustersOutput{
+			Clusters: []redshifttypes.Cluster{
+				newCluster("warehouse", "Environment", "production", "Team", "analytics"),
+				newCluster("staging-warehouse", "Environment", "staging", "Team", "analytics"),
+			},
+			Marker: aws.String("next"),
+		}, nil).Once()
+		mockRedshift.On("DescribeClusters", mock.Anything, mock.MatchedBy(func(input *redshift.DescribeClustersInput) bool {
+			return aws.ToString(input.Marker) == "next"
+		}), mock.Anything).Return(&redshift.DescribeClustersOutput{
+			Clusters: []redshifttypes.Cluster{
+				func() redshifttypes.Cluster {
+					cluster := newCluster("creating", "Environment", "production", "Team", "analytics")
+					cluster.ClusterStatus = aws.String("creating")
+					cluster.Endpoint = nil
+					return cluster
+				}(),
+				func() redshifttypes.Cluster {
+					cluster := newCluster("reports", "Environment", "production", "Team", "analytics")
+					cluster.DBName = aws.String("reports")
+					cluster.NumberOfNodes = aws.Int32(1)
+					cluster.Encrypted = aws.Bool(false)
+					return cluster
+				}(),
+			},
+		}, nil).Once()
+
+		result, err := provider.Discover(ctx, providers.ProviderConfig{
+			Region: "ap-northeast-1",
+			Filters: map[string]interface{}{
+				"tags": map[string]interface{}{"Environment": "production", "Team": "analytics"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		resource := result[0]
+		assert.Equal(t, "warehouse.abc123.ap-northeast-1.redshift.amazonaws.com", resource.Host)
+		assert.Equal(t, 5439, resource.Port)
+		assert.Equal(t, "production", resource.Tags["Environment"])
+		assert.Equal(t, "analytics", resource.Tags["Team"])
+		assert.Equal(t, "warehouse", resource.Metadata["ClusterIdentifier"])
+		assert.Equal(t, "dev", resource.Metadata["DBName"])
+		assert.Equal(t, "ra3.xlplus", resource.Metadata["NodeType"])
+		assert.Equal(t, 2, resource.Metadata["NumberOfNodes"])
+		assert.Equal(t, true, resource.Metadata["Encrypted"])
+		assert.Equal(t, "available", resource.Metadata["ClusterStatus"])
+		assert.Equal(t, "1.0", resource.Metadata["ClusterVersion"])
+		assert.Equal(t, "ap-northeast-1a", resource.Metadata["AvailabilityZone"])
+		assert.Equal(t, "vpc-0123", resource.Metadata["VpcID"])
+
+		assert.Equal(t, "reports", result[1].Metadata["ClusterIdentifier"])
+		assert.Equal(t, "reports", result[1].Metadata["DBName"])
+		assert.Equal(t, 1, result[1].Metadata["NumberOfNodes"])
+		assert.Equal(t, false, result[1].Metadata["Encrypted"])
+
+		mockRedshift.AssertExpectations(t)
+	})
+
+	t.Run("no tag filters", func(t *testing.T) {
+		mockRedshift := new(MockRedshiftClient)
+		provider := newTestProvider(mockRedshift)
+
+		mockRedshift.On("DescribeClusters", mock.Anything, mock.MatchedBy(func(input *redshift.DescribeClustersInput) bool {
+			return input.TagKeys == nil && input.TagValues == nil
+		}), mock.Anything).Return(&redshift.DescribeClustersOutput{
+			Clusters: []redshifttypes.Cluster{newCluster("warehouse")},
+		}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Empty(t, result[0].Tags)
+		mockRedshift.AssertExpectations(t)
+	})
+
+	t.Run("no clusters", func(t *testing.T) {
+		mockRedshift := new(MockRedshiftClient)
+		provider := newTestProvider(mockRedshift)
+
+		mockRedshift.On("DescribeClusters", mock.Anything, mock.Anything, mock.Anything).Return(&redshift.DescribeClustersOutput{}, nil)
+
+		result, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Empty(t, result)
+	})
+
+	t.Run("describe error", func(t *testing.T) {
+		mockRedshift := new(MockRedshiftClient)
+		provider := newTestProvider(mockRedshift)
+
+		mockRedshift.On("DescribeClusters", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		_, err := provider.Discover(context.Background(), providers.ProviderConfig{Region: "ap-northeast-1"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "failed to describe Redshift clusters")
+	})
+}
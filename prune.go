@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/moepig/dd-conf-gen/state"
+	"github.com/moepig/dd-conf-gen/writer"
+)
+
+// defaultStateFileSuffix is appended to the config file name to name the state
+// file created next to the generation config when -state-file is not given
+const defaultStateFileSuffix = ".state.json"
+
+// defaultStateFile returns the default state file path for a generation config,
+// e.g. .redis.yaml.state.json for redis.yaml. Each config gets its own state file
+// so that configs in the same directory never prune each other's outputs.
+func defaultStateFile(configPath string) string {
+	return filepath.Join(filepath.Dir(configPath), "."+filepath.Base(configPath)+defaultStateFileSuffix)
+}
+
+// loadState loads the state file of a generation config.
+// A state file written by another generation config is refused.
+func loadState(configPath string, opts runOptions) (*state.Manifest, error) {
+	manifest, err := state.Load(opts.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config file '%s': %w", configPath, err)
+	}
+	if manifest.Config != "" && manifest.Config != absConfigPath {
+		return nil, fmt.Errorf("state file '%s' belongs to config '%s', not '%s' (use a separate -state-file for each config)",
+			opts.StateFile, manifest.Config, absConfigPath)
+	}
+	manifest.Config = absConfigPath
+
+	return manifest, nil
+}
+
+// saveState writes the state file
+func saveState(manifest *state.Manifest, opts runOptions) error {
+	if err := state.Save(opts.StateFile, manifest); err != nil {
+		return err
+	}
+	slog.Debug("Updated state file", "path", opts.StateFile, "files", len(manifest.Files), "pending_hooks", len(manifest.PendingHooks))
+	return nil
+}
+
+// reconcileManagedFiles compares the outputs of the generation config with the
+// files recorded in the manifest. Files that were generated before but are no
+// longer configured are removed (or quarantined) when pruning is enabled, and
+// reported otherwise. The manifest is then updated with the current outputs,
+// except in dry-run mode.
+// It reports whether any file was, or in dry-run mode would be, removed.
+func reconcileManagedFiles(manifest *state.Manifest, genCfg *config.GenConfig, opts runOptions) (bool, error) {
+	current := make([]string, 0, len(genCfg.Outputs))
+	for _, outCfg := range genCfg.Outputs {
+		path, err := filepath.Abs(outCfg.OutputFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to resolve output file '%s': %w", outCfg.OutputFile, err)
+		}
+		current = append(current, path)
+	}
+
+	orphans := manifest.Orphans(current)
+	managed := current
+	removed := false
+
+	for _, orphan := range orphans {
+		if !opts.Prune {
+			slog.Warn("Output file is no longer configured (use -prune to remove it)", "path", orphan)
+			managed = append(managed, orphan)
+			continue
+		}
+
+		if opts.DryRun {
+			diff, err := writer.DiffRemoval(orphan)
+			if err != nil {
+				return false, err
+			}
+			fmt.Print(diff)
+			removed = true
+			slog.Info("Dry run: orphaned output file not removed", "path", orphan)
+			continue
+		}
+
+		if err := state.Remove(orphan, opts.QuarantineDir); err != nil {
+			// Keep tracking the file so that the next run retries
+			slog.Error("Failed to prune orphaned output file", "path", orphan, "error", err)
+			managed = append(managed, orphan)
+			continue
+		}
+		removed = true
+		if opts.QuarantineDir != "" {
+			slog.Info("Quarantined orphaned output file", "path", orphan, "quarantine_dir", opts.QuarantineDir)
+		} else {
+			slog.Info("Removed orphaned output file", "path", orphan)
+		}
+	}
+
+	if !opts.DryRun {
+		manifest.Files = managed
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/moepig/dd-conf-gen/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pruneFixture is a directory with a generation config, its state file and output files
+type pruneFixture struct {
+	dir        string
+	configPath string
+	opts       runOptions
+}
+
+// newPruneFixture creates a fixture whose state file already records the given outputs
+func newPruneFixture(t *testing.T, recorded ...string) *pruneFixture {
+	t.Helper()
+
+	dir := t.TempDir()
+	f := &pruneFixture{
+		dir:        dir,
+		configPath: filepath.Join(dir, "gen-config.yaml"),
+	}
+	f.opts = runOptions{StateFile: defaultStateFile(f.configPath)}
+
+	var files []string
+	for _, name := range recorded {
+		path := f.path(name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("instances:\n  - host: "+name+"\n"), 0644))
+		files = append(files, path)
+	}
+	require.NoError(t, state.Save(f.opts.StateFile, &state.Manifest{Config: f.configPath, Files: files}))
+	return f
+}
+
+// path returns the absolute path of an output file in the fixture
+func (f *pruneFixture) path(name string) string {
+	return filepath.Join(f.dir, name)
+}
+
+// genConfig returns a generation config with the given outputs
+func (f *pruneFixture) genConfig(outputs ...string) *config.GenConfig {
+	genCfg := &config.GenConfig{}
+	for _, name := range outputs {
+		genCfg.Outputs = append(genCfg.Outputs, config.OutputConfig{OutputFile: f.path(name)})
+	}
+	return genCfg
+}
+
+// managedFiles returns the files recorded in the state file
+func (f *pruneFixture) managedFiles(t *testing.T) []string {
+	t.Helper()
+
+	manifest, err := state.Load(f.opts.StateFile)
+	require.NoError(t, err)
+	return manifest.Files
+}
+
+// reconcile loads the state file, reconciles it with genCfg and saves it unless in dry-run mode
+func reconcile(genCfg *config.GenConfig, configPath string, opts runOptions) (bool, error) {
+	manifest, err := loadState(configPath, opts)
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := reconcileManagedFiles(manifest, genCfg, opts)
+	if err != nil || opts.DryRun {
+		return removed, err
+	}
+	return removed, saveState(manifest, opts)
+}
+
+func TestDefaultStateFile(t *testing.T) {
+	assert.Equal(t, "/etc/dd-conf-gen/.redis.yaml.state.json", defaultStateFile("/etc/dd-conf-gen/redis.yaml"))
+	assert.NotEqual(t, defaultStateFile("/etc/dd-conf-gen/redis.yaml"), defaultStateFile("/etc/dd-conf-gen/rds.yaml"))
+}
+
+func TestReconcileManagedFiles(t *testing.T) {
+	t.Run("new outputs are recorded", func(t *testing.T) {
+		dir := t.TempDir()
+		configPath := filepath.Join(dir, "gen-config.yaml")
+		opts := runOptions{StateFile: defaultStateFile(configPath)}
+		genCfg := &config.GenConfig{Outputs: []config.OutputConfig{{OutputFile: filepath.Join(dir, "a.yaml")}}}
+
+		removed, err := reconcile(genCfg, configPath, opts)
+		require.NoError(t, err)
+		assert.False(t, removed)
+
+		manifest, err := state.Load(opts.StateFile)
+		require.NoError(t, err)
+		assert.Equal(t, configPath, manifest.Config)
+		assert.Equal(t, []string{filepath.Join(dir, "a.yaml")}, manifest.Files)
+	})
+
+	t.Run("orphans are kept without prune", func(t *testing.T) {
+		f := newPruneFixture(t, "a.yaml", "b.yaml")
+
+		removed, err := reconcile(f.genConfig("a.yaml"), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.False(t, removed)
+
+		assert.FileExists(t, f.path("b.yaml"))
+		assert.Equal(t, []string{f.path("a.yaml"), f.path("b.yaml")}, f.managedFiles(t))
+	})
+
+	t.Run("orphans are removed with prune", func(t *testing.T) {
+		f := newPruneFixture(t, "a.yaml", "b.yaml")
+		f.opts.Prune = true
+
+		removed, err := reconcile(f.genConfig("a.yaml"), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		assert.FileExists(t, f.path("a.yaml"))
+		assert.NoFileExists(t, f.path("b.yaml"))
+		assert.Equal(t, []string{f.path("a.yaml")}, f.managedFiles(t))
+	})
+
+	t.Run("orphans are quarantined with prune", func(t *testing.T) {
+		f := newPruneFixture(t, "a/conf.d/redisdb.yaml", "b/conf.d/redisdb.yaml")
+		f.opts.Prune = true
+		f.opts.QuarantineDir = filepath.Join(t.TempDir(), "quarantine")
+
+		removed, err := reconcile(f.genConfig(), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		for _, name := range []string{"a/conf.d/redisdb.yaml", "b/conf.d/redisdb.yaml"} {
+			assert.NoFileExists(t, f.path(name))
+			matches, err := filepath.Glob(filepath.Join(f.opts.QuarantineDir, "*", f.path(name)))
+			require.NoError(t, err)
+			assert.Len(t, matches, 1)
+		}
+		assert.Empty(t, f.managedFiles(t))
+	})
+
+	t.Run("dry run leaves files and state untouched", func(t *testing.T) {
+		f := newPruneFixture(t, "a.yaml", "b.yaml")
+		f.opts.Prune = true
+		f.opts.DryRun = true
+		before, err := os.ReadFile(f.opts.StateFile)
+		require.NoError(t, err)
+
+		removed, err := reconcile(f.genConfig("a.yaml", "c.yaml"), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		assert.FileExists(t, f.path("b.yaml"))
+		after, err := os.ReadFile(f.opts.StateFile)
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after))
+	})
+
+	t.Run("failed removal is retried on the next run", func(t *testing.T) {
+		f := newPruneFixture(t, "a.yaml")
+		f.opts.Prune = true
+
+		// A non-empty directory cannot be removed
+		blocked := f.path("b.yaml")
+		require.NoError(t, os.MkdirAll(filepath.Join(blocked, "child"), 0755))
+		require.NoError(t, state.Save(f.opts.StateFile, &state.Manifest{
+			Config: f.configPath,
+			Files:  []string{f.path("a.yaml"), blocked},
+		}))
+
+		removed, err := reconcile(f.genConfig("a.yaml"), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.False(t, removed)
+		assert.Equal(t, []string{f.path("a.yaml"), blocked}, f.managedFiles(t))
+
+		require.NoError(t, os.Remove(filepath.Join(blocked, "child")))
+
+		removed, err = reconcile(f.genConfig("a.yaml"), f.configPath, f.opts)
+		require.NoError(t, err)
+		assert.True(t, removed)
+		assert.NoDirExists(t, blocked)
+		assert.Equal(t, []string{f.path("a.yaml")}, f.managedFiles(t))
+	})
+
+	t.Run("state file of another config is refused", func(t *testing.T) {
+		f := newPruneFixture(t, "a.yaml")
+		f.opts.Prune = true
+		otherConfig := filepath.Join(f.dir, "other.yaml")
+
+		_, err := reconcile(f.genConfig(), otherConfig, f.opts)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "belongs to config '"+f.configPath+"'")
+		assert.FileExists(t, f.path("a.yaml"))
+	})
+
+	t.Run("configs in the same directory do not prune each other", func(t *testing.T) {
+		dir := t.TempDir()
+		redisConfig := filepath.Join(dir, "redis.yaml")
+		rdsConfig := filepath.Join(dir, "rds.yaml")
+		redisOutput := filepath.Join(dir, "redisdb.yaml")
+		require.NoError(t, os.WriteFile(redisOutput, []byte("instances: []\n"), 0644))
+
+		redisCfg := &config.GenConfig{Outputs: []config.OutputConfig{{OutputFile: redisOutput}}}
+		rdsCfg := &config.GenConfig{Outputs: []config.OutputConfig{{OutputFile: filepath.Join(dir, "postgres.yaml")}}}
+
+		_, err := reconcile(redisCfg, redisConfig, runOptions{StateFile: defaultStateFile(redisConfig)})
+		require.NoError(t, err)
+		removed, err := reconcile(rdsCfg, rdsConfig, runOptions{StateFile: defaultStateFile(rdsConfig), Prune: true})
+		require.NoError(t, err)
+		assert.False(t, removed)
+		assert.FileExists(t, redisOutput)
+	})
+}
@@ -29,6 +29,30 @@ func NewRenderer(templateDir string) *Renderer {
 
 // Render renders a template with the given data
 func (r *Renderer) Render(templatePath string, data TemplateData) ([]byte, error) {
+	tmpl, err := r.parse(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Rendering template with data", "resources_count", len(data.Resources))
+
+	// Execute template
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Validate checks that a template can be read and parsed without executing it
+func (r *Renderer) Validate(templatePath string) error {
+	_, err := r.parse(templatePath)
+	return err
+}
+
+// parse reads and parses a template file
+func (r *Renderer) parse(templatePath string) (*template.Template, error) {
 	// Read template file
 	content, err := os.ReadFile(templatePath)
 	if err != nil {
@@ -43,13 +67,5 @@ func (r *Renderer) Render(templatePath string, data TemplateData) ([]byte, error
 		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	slog.Debug("Rendering template with data", "resources_count", len(data.Resources))
-
-	// Execute template
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return tmpl, nil
 }
@@ -288,6 +288,39 @@ instances:
 	})
 }
 
+func TestRenderer_Validate(t *testing.T) {
+	t.Run("valid template", func(t *testing.T) {
+		templateContent := `instances:
+{{- range .Resources }}
+  - host: {{ .Host }}
+{{- end }}
+`
+		tmpfile := createTempFile(t, templateContent)
+		defer os.Remove(tmpfile)
+
+		renderer := NewRenderer("")
+		assert.NoError(t, renderer.Validate(tmpfile))
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		renderer := NewRenderer("")
+
+		err := renderer.Validate("/nonexistent/template.yaml")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read template file")
+	})
+
+	t.Run("invalid template syntax", func(t *testing.T) {
+		tmpfile := createTempFile(t, `{{ range .Resources }}`)
+		defer os.Remove(tmpfile)
+
+		renderer := NewRenderer("")
+		err := renderer.Validate(tmpfile)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse template")
+	})
+}
+
 func createTempFile(t *testing.T, content string) string {
 	tmpfile, err := os.CreateTemp("", "template-*.yaml")
 	require.NoError(t, err)
@@ -0,0 +1,187 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/moepig/dd-conf-gen/writer"
+)
+
+// manifestVersion is the current manifest file format version
+const manifestVersion = 1
+
+// rename moves a file; replaced in tests to simulate cross-device moves
+var rename = os.Rename
+
+// Manifest records the output files generated by dd-conf-gen
+type Manifest struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Config is the absolute path of the generation config that wrote the manifest
+	Config string   `json:"config,omitempty"`
+	Files  []string `json:"files"`
+	// PendingHooks are the output files whose on_change hooks have not succeeded yet
+	PendingHooks []string `json:"pending_hooks,omitempty"`
+	// PendingGlobalHooks is set while the top-level on_change hooks have not succeeded yet
+	PendingGlobalHooks bool `json:"pending_global_hooks,omitempty"`
+}
+
+// Load reads a manifest file. A missing file yields an empty manifest.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Manifest{Version: manifestVersion}, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse state file '%s': %w", path, err)
+	}
+	if m.Version > manifestVersion {
+		return nil, fmt.Errorf("unsupported state file version %d in '%s'", m.Version, path)
+	}
+
+	return &m, nil
+}
+
+// Save writes the manifest atomically
+func Save(path string, m *Manifest) error {
+	m.Version = manifestVersion
+	m.UpdatedAt = time.Now().UTC()
+
+	m.Files = sortedUnique(m.Files)
+	m.PendingHooks = sortedUnique(m.PendingHooks)
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state file: %w", err)
+	}
+	data = append(data, '\n')
+
+	if _, err := writer.Write(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// sortedUnique returns a sorted copy of list without duplicates
+func sortedUnique(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	result := slices.Clone(list)
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// Orphans returns the managed files that are not in current, in manifest order
+func (m *Manifest) Orphans(current []string) []string {
+	var orphans []string
+	for _, file := range m.Files {
+		if !slices.Contains(current, file) {
+			orphans = append(orphans, file)
+		}
+	}
+	return orphans
+}
+
+// Remove deletes an orphaned file, or moves it into quarantineDir when set.
+// Quarantined files keep their absolute path under a timestamped directory, so
+// that orphans with the same name in different directories never collide.
+// A file that no longer exists is not an error.
+func Remove(path, quarantineDir string) error {
+	if quarantineDir == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove '%s': %w", path, err)
+		}
+		return nil
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	dest, err := quarantinePath(path, quarantineDir, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create quarantine directory '%s': %w", filepath.Dir(dest), err)
+	}
+
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("failed to move '%s' to quarantine: '%s' already exists", path, dest)
+	}
+	if err := rename(path, dest); err != nil {
+		if !errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("failed to move '%s' to quarantine: %w", path, err)
+		}
+		// The quarantine directory is on another filesystem
+		if err := copyAndRemove(path, dest); err != nil {
+			return fmt.Errorf("failed to move '%s' to quarantine: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// copyAndRemove copies src to dest, syncs it to disk and then removes src.
+// A partially written dest is removed on failure.
+func copyAndRemove(src, dest string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(dest)
+		}
+	}()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(src)
+}
+
+// quarantinePath returns the destination of path in quarantineDir, e.g.
+// <quarantineDir>/20060102T150405Z/etc/datadog-agent/conf.d/redisdb.d/conf.yaml
+func quarantinePath(path, quarantineDir string, now time.Time) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve '%s': %w", path, err)
+	}
+	rel := strings.TrimLeft(abs[len(filepath.VolumeName(abs)):], string(filepath.Separator))
+	return filepath.Join(quarantineDir, now.UTC().Format("20060102T150405Z"), rel), nil
+}
@@ -0,0 +1,157 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSave(t *testing.T) {
+	t.Run("missing file yields empty manifest", func(t *testing.T) {
+		m, err := Load(filepath.Join(t.TempDir(), "state.json"))
+		require.NoError(t, err)
+		assert.Empty(t, m.Files)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state", "state.json")
+
+		err := Save(path, &Manifest{Files: []string{"/etc/b.yaml", "/etc/a.yaml", "/etc/b.yaml"}})
+		require.NoError(t, err)
+
+		m, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, manifestVersion, m.Version)
+		assert.Equal(t, []string{"/etc/a.yaml", "/etc/b.yaml"}, m.Files)
+		assert.False(t, m.UpdatedAt.IsZero())
+	})
+
+	t.Run("pending hooks round trip", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+
+		err := Save(path, &Manifest{
+			Config:             "/etc/dd-conf-gen/redis.yaml",
+			Files:              []string{"/etc/a.yaml", "/etc/b.yaml"},
+			PendingHooks:       []string{"/etc/b.yaml", "/etc/a.yaml", "/etc/b.yaml"},
+			PendingGlobalHooks: true,
+		})
+		require.NoError(t, err)
+
+		m, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "/etc/dd-conf-gen/redis.yaml", m.Config)
+		assert.Equal(t, []string{"/etc/a.yaml", "/etc/b.yaml"}, m.PendingHooks)
+		assert.True(t, m.PendingGlobalHooks)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+
+		_, err := Load(path)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse state file")
+	})
+
+	t.Run("newer version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "files": []}`), 0644))
+
+		_, err := Load(path)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported state file version 99")
+	})
+}
+
+func TestManifest_Orphans(t *testing.T) {
+	m := &Manifest{Files: []string{"/etc/a.yaml", "/etc/b.yaml", "/etc/c.yaml"}}
+
+	assert.Equal(t, []string{"/etc/a.yaml", "/etc/c.yaml"}, m.Orphans([]string{"/etc/b.yaml", "/etc/d.yaml"}))
+	assert.Empty(t, m.Orphans([]string{"/etc/a.yaml", "/etc/b.yaml", "/etc/c.yaml"}))
+}
+
+func TestRemove(t *testing.T) {
+	t.Run("delete", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "old.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("instances:\n"), 0644))
+
+		require.NoError(t, Remove(path, ""))
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("quarantine", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "old.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("instances:\n"), 0644))
+		quarantineDir := filepath.Join(t.TempDir(), "quarantine")
+
+		require.NoError(t, Remove(path, quarantineDir))
+		assert.NoFileExists(t, path)
+
+		runs, err := os.ReadDir(quarantineDir)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+
+		content, err := os.ReadFile(filepath.Join(quarantineDir, runs[0].Name(), path))
+		require.NoError(t, err)
+		assert.Equal(t, "instances:\n", string(content))
+	})
+
+	t.Run("quarantine keeps files with the same name apart", func(t *testing.T) {
+		dir := t.TempDir()
+		first := filepath.Join(dir, "a", "conf.d", "redisdb.yaml")
+		second := filepath.Join(dir, "b", "conf.d", "redisdb.yaml")
+		for _, path := range []string{first, second} {
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(path), 0644))
+		}
+		quarantineDir := filepath.Join(t.TempDir(), "quarantine")
+
+		require.NoError(t, Remove(first, quarantineDir))
+		require.NoError(t, Remove(second, quarantineDir))
+
+		for _, path := range []string{first, second} {
+			matches, err := filepath.Glob(filepath.Join(quarantineDir, "*", path))
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			content, err := os.ReadFile(matches[0])
+			require.NoError(t, err)
+			assert.Equal(t, path, string(content))
+		}
+	})
+
+	t.Run("quarantine on another filesystem", func(t *testing.T) {
+		orig := rename
+		t.Cleanup(func() { rename = orig })
+		rename = func(oldpath, newpath string) error {
+			return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+		}
+
+		path := filepath.Join(t.TempDir(), "old.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("instances:\n"), 0600))
+		require.NoError(t, os.Chmod(path, 0600))
+		quarantineDir := filepath.Join(t.TempDir(), "quarantine")
+
+		require.NoError(t, Remove(path, quarantineDir))
+		assert.NoFileExists(t, path)
+
+		matches, err := filepath.Glob(filepath.Join(quarantineDir, "*", path))
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		content, err := os.ReadFile(matches[0])
+		require.NoError(t, err)
+		assert.Equal(t, "instances:\n", string(content))
+		info, err := os.Stat(matches[0])
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("already removed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing.yaml")
+		assert.NoError(t, Remove(path, ""))
+		assert.NoError(t, Remove(path, filepath.Join(t.TempDir(), "quarantine")))
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/moepig/dd-conf-gen/providers"
+	"github.com/moepig/dd-conf-gen/renderer"
+)
+
+// validateMain runs the validate subcommand and returns the process exit code
+func validateMain(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := fs.String("config", "", "Path to generation configuration file")
+	logLevelStr := fs.String("log-level", "info", "Log level (debug, info, warn, error)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s validate [options]\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	if err := setupLogger(*logLevelStr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fs.Usage()
+		return 1
+	}
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -config option is required")
+		fs.Usage()
+		return 1
+	}
+
+	if err := runValidate(*configPath); err != nil {
+		slog.Error("Validation failed", "error", err)
+		return 1
+	}
+
+	return 0
+}
+
+// runValidate checks the generation config, provider configurations and templates
+// without calling any AWS API
+func runValidate(configPath string) error {
+	slog.Info("Loading generation configuration", "config_path", configPath)
+	genCfg, err := config.LoadGenConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load generation config: %w", err)
+	}
+
+	var errs []error
+	if err := validateProviders(genCfg); err != nil {
+		errs = append(errs, err)
+	}
+
+	rend := renderer.NewRenderer("")
+	for _, outCfg := range genCfg.Outputs {
+		templatePath := resolveTemplatePath(configPath, outCfg.Template)
+		slog.Debug("Validating template", "template", templatePath, "output_file", outCfg.OutputFile)
+		if err := rend.Validate(templatePath); err != nil {
+			errs = append(errs, fmt.Errorf("invalid template for '%s': %w", outCfg.OutputFile, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	slog.Info("Configuration is valid",
+		"resources", len(genCfg.Resources),
+		"outputs", len(genCfg.Outputs))
+	return nil
+}
+
+// validateProviders checks that every resource has a registered provider
+// and that the provider accepts its configuration
+func validateProviders(genCfg *config.GenConfig) error {
+	var errs []error
+	for _, resCfg := range genCfg.Resources {
+		provider, err := providers.Get(resCfg.Type)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get provider for resource '%s': %w", resCfg.Name, err))
+			continue
+		}
+
+		if err := provider.ValidateConfig(newProviderConfig(resCfg)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid configuration for resource '%s': %w", resCfg.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeValidateFixture writes a generation config and the templates it uses into a
+// temporary directory and returns the config path
+func writeValidateFixture(t *testing.T, genConfig string, templates map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range templates {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	configPath := filepath.Join(dir, "gen-config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(genConfig), 0644))
+	return configPath
+}
+
+func TestRunValidate(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		configPath := writeValidateFixture(t, `
+resources:
+  - name: redis
+    type: test_static
+    region: ap-northeast-1
+    filters:
+      count: 2
+
+outputs:
+  - template: check.yaml.tmpl
+    output_file: /etc/datadog-agent/conf.d/redisdb.d/conf.yaml
+    data:
+      resource_name: redis
+`, map[string]string{"check.yaml.tmpl": checkTemplate})
+
+		assert.NoError(t, runValidate(configPath))
+	})
+
+	t.Run("errors are reported together", func(t *testing.T) {
+		configPath := writeValidateFixture(t, `
+resources:
+  - name: payments
+    type: cloudmap_instances
+    region: ap-northeast-1
+  - name: unknown
+    type: no_such_provider
+    region: ap-northeast-1
+
+outputs:
+  - template: broken.yaml.tmpl
+    output_file: /etc/datadog-agent/conf.d/http_check.d/conf.yaml
+    data:
+      resource_name: payments
+`, map[string]string{"broken.yaml.tmpl": "instances:\n{{- range .Resources }\n"})
+
+		err := runValidate(configPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid configuration for resource 'payments': filters.namespace is required")
+		assert.Contains(t, err.Error(), "failed to get provider for resource 'unknown': provider not found for resource type: no_such_provider")
+		assert.Contains(t, err.Error(), "invalid template for '/etc/datadog-agent/conf.d/http_check.d/conf.yaml'")
+	})
+
+	t.Run("config cannot be loaded", func(t *testing.T) {
+		err := runValidate(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load generation config")
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/moepig/dd-conf-gen/config"
+)
+
+// maxJitterFraction is the maximum random delay added to each watch interval,
+// as a fraction of the interval, so that many hosts do not hit AWS at once
+const maxJitterFraction = 0.1
+
+// watch runs the generation pipeline every interval until ctx is cancelled.
+// SIGHUP reloads the generation configuration and triggers an immediate run;
+// if the reload fails the previous configuration is kept and the next run
+// happens on the next tick. Failed runs are logged and retried on the next
+// tick; outputs are only rewritten when their rendered content changes.
+func watch(ctx context.Context, configPath string, opts runOptions, interval time.Duration) error {
+	genCfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	reload := func() (*config.GenConfig, error) {
+		return loadConfig(configPath)
+	}
+	run := func(ctx context.Context, genCfg *config.GenConfig) error {
+		_, err := generate(ctx, genCfg, configPath, opts)
+		return err
+	}
+
+	slog.Info("Starting watch mode", "interval", interval)
+	watchLoop(ctx, genCfg, reload, run, hup, interval)
+	slog.Info("Stopping watch mode")
+	return nil
+}
+
+// watchLoop calls run with the current configuration, then waits for the next
+// tick or a signal on hup, which replaces the configuration with the result of
+// reload. It returns when ctx is cancelled.
+func watchLoop(
+	ctx context.Context,
+	genCfg *config.GenConfig,
+	reload func() (*config.GenConfig, error),
+	run func(context.Context, *config.GenConfig) error,
+	hup <-chan os.Signal,
+	interval time.Duration,
+) {
+	for {
+		if err := run(ctx, genCfg); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Error("Generation failed", "error", err)
+		}
+
+		delay := nextDelay(interval)
+		slog.Info("Waiting for next run", "delay", delay)
+		timer := time.NewTimer(delay)
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-hup:
+				slog.Info("Received SIGHUP, reloading generation configuration")
+				reloaded, err := reload()
+				if err != nil {
+					slog.Error("Failed to reload generation config, keeping previous configuration", "error", err)
+					continue
+				}
+				timer.Stop()
+				genCfg = reloaded
+				break wait
+			case <-timer.C:
+				break wait
+			}
+		}
+	}
+}
+
+// nextDelay returns the interval plus a random jitter of up to maxJitterFraction
+func nextDelay(interval time.Duration) time.Duration {
+	maxJitter := int64(float64(interval) * maxJitterFraction)
+	if maxJitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int64N(maxJitter))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/moepig/dd-conf-gen/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextDelay(t *testing.T) {
+	t.Run("jitter stays within the allowed fraction", func(t *testing.T) {
+		interval := time.Minute
+		for i := 0; i < 1000; i++ {
+			delay := nextDelay(interval)
+			assert.GreaterOrEqual(t, delay, interval)
+			assert.Less(t, delay, interval+interval/10)
+		}
+	})
+
+	t.Run("interval too short for jitter", func(t *testing.T) {
+		assert.Equal(t, 5*time.Nanosecond, nextDelay(5*time.Nanosecond))
+	})
+}
+
+// watchLoopFixture runs watchLoop in the background and records each run
+type watchLoopFixture struct {
+	cancel context.CancelFunc
+	hup    chan os.Signal
+	runs   chan *config.GenConfig
+	done   chan struct{}
+}
+
+func startWatchLoop(t *testing.T, genCfg *config.GenConfig, reload func() (*config.GenConfig, error), interval time.Duration) *watchLoopFixture {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &watchLoopFixture{
+		cancel: cancel,
+		hup:    make(chan os.Signal, 1),
+		runs:   make(chan *config.GenConfig, 10),
+		done:   make(chan struct{}),
+	}
+	run := func(ctx context.Context, genCfg *config.GenConfig) error {
+		select {
+		case f.runs <- genCfg:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	go func() {
+		defer close(f.done)
+		watchLoop(ctx, genCfg, reload, run, f.hup, interval)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-f.done
+	})
+	return f
+}
+
+// nextRun waits for the next run and returns the configuration it used
+func (f *watchLoopFixture) nextRun(t *testing.T) *config.GenConfig {
+	t.Helper()
+	select {
+	case genCfg := <-f.runs:
+		return genCfg
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for a run")
+		return nil
+	}
+}
+
+func TestWatchLoop(t *testing.T) {
+	initial := &config.GenConfig{Resources: []config.ResourceConfig{{Name: "initial"}}}
+	reloaded := &config.GenConfig{Resources: []config.ResourceConfig{{Name: "reloaded"}}}
+
+	t.Run("runs on every tick", func(t *testing.T) {
+		f := startWatchLoop(t, initial, nil, time.Millisecond)
+
+		for i := 0; i < 3; i++ {
+			assert.Same(t, initial, f.nextRun(t))
+		}
+	})
+
+	t.Run("context cancel stops the loop", func(t *testing.T) {
+		f := startWatchLoop(t, initial, nil, time.Hour)
+		f.nextRun(t)
+
+		f.cancel()
+		select {
+		case <-f.done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "watch loop did not stop after cancel")
+		}
+	})
+
+	t.Run("SIGHUP swaps in the reloaded configuration", func(t *testing.T) {
+		reload := func() (*config.GenConfig, error) {
+			return reloaded, nil
+		}
+		f := startWatchLoop(t, initial, reload, time.Hour)
+		assert.Same(t, initial, f.nextRun(t))
+
+		f.hup <- syscall.SIGHUP
+		assert.Same(t, reloaded, f.nextRun(t))
+	})
+
+	t.Run("failed reload keeps the configuration and waits for the next tick", func(t *testing.T) {
+		reloads := make(chan struct{}, 1)
+		reload := func() (*config.GenConfig, error) {
+			reloads <- struct{}{}
+			return nil, errors.New("invalid configuration")
+		}
+		f := startWatchLoop(t, initial, reload, time.Hour)
+		f.nextRun(t)
+
+		f.hup <- syscall.SIGHUP
+		select {
+		case <-reloads:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for the reload")
+		}
+
+		select {
+		case <-f.runs:
+			assert.Fail(t, "failed reload should not trigger a run")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+}
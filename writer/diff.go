@@ -0,0 +1,90 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Diff returns a unified diff between the existing file at path and content.
+// A missing file is treated as empty. The boolean result reports whether
+// writing content would change the file.
+func Diff(path string, content []byte) (string, bool, error) {
+	current, err := os.ReadFile(path)
+	exists := true
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", false, fmt.Errorf("failed to read existing file '%s': %w", path, err)
+		}
+		exists = false
+	}
+
+	if exists && bytes.Equal(current, content) {
+		return "", false, nil
+	}
+
+	fromFile := path
+	if !exists {
+		fromFile = "/dev/null"
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        splitLines(content),
+		FromFile: fromFile,
+		ToFile:   path,
+		Context:  3,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("failed to compute diff for '%s': %w", path, err)
+	}
+
+	return diff, true, nil
+}
+
+// DiffRemoval returns a unified diff that deletes the existing file at path
+func DiffRemoval(path string) (string, error) {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read existing file '%s': %w", path, err)
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(current),
+		B:        nil,
+		FromFile: path,
+		ToFile:   "/dev/null",
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to compute diff for '%s': %w", path, err)
+	}
+
+	return diff, nil
+}
+
+// splitLines splits content into lines that keep their trailing newline.
+// Unlike difflib.SplitLines, empty content yields no lines, so diffs against
+// missing files have correct hunk ranges.
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		// Terminate the last line so that the diff output stays line-oriented
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
@@ -0,0 +1,83 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+
+		diff, changed, err := Diff(path, []byte("init_config:\n"))
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Contains(t, diff, "--- /dev/null")
+		assert.Contains(t, diff, "+++ "+path)
+		assert.Contains(t, diff, "@@ -0,0 +1 @@")
+		assert.Contains(t, diff, "+init_config:")
+	})
+
+	t.Run("unchanged file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("init_config:\n"), 0644))
+
+		diff, changed, err := Diff(path, []byte("init_config:\n"))
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Empty(t, diff)
+	})
+
+	t.Run("modified file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		existing := "instances:\n  - host: old.example.com\n    port: 6379\n"
+		require.NoError(t, os.WriteFile(path, []byte(existing), 0644))
+
+		updated := "instances:\n  - host: new.example.com\n    port: 6379\n"
+		diff, changed, err := Diff(path, []byte(updated))
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Contains(t, diff, "--- "+path)
+		assert.Contains(t, diff, "-  - host: old.example.com")
+		assert.Contains(t, diff, "+  - host: new.example.com")
+		assert.Contains(t, diff, "     port: 6379")
+	})
+
+	t.Run("unreadable path", func(t *testing.T) {
+		_, _, err := Diff(t.TempDir(), []byte("init_config:\n"))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read existing file")
+	})
+}
+
+func TestDiffRemoval(t *testing.T) {
+	t.Run("existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("init_config:\ninstances:\n"), 0644))
+
+		diff, err := DiffRemoval(path)
+		require.NoError(t, err)
+		assert.Contains(t, diff, "--- "+path)
+		assert.Contains(t, diff, "+++ /dev/null")
+		assert.Contains(t, diff, "@@ -1,2 +0,0 @@")
+		assert.Contains(t, diff, "-init_config:")
+		assert.Contains(t, diff, "-instances:")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		diff, err := DiffRemoval(filepath.Join(t.TempDir(), "redisdb.yaml"))
+		require.NoError(t, err)
+		assert.Empty(t, diff)
+	})
+}
+
+func TestSplitLines(t *testing.T) {
+	assert.Nil(t, splitLines(nil))
+	assert.Equal(t, []string{"a\n", "b\n"}, splitLines([]byte("a\nb\n")))
+	assert.Equal(t, []string{"a\n", "b\n"}, splitLines([]byte("a\nb")))
+	assert.Equal(t, []string{"\n"}, splitLines([]byte("\n")))
+}
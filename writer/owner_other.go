@@ -0,0 +1,13 @@
+//go:build !unix
+
+package writer
+
+import (
+	"io/fs"
+	"os"
+)
+
+// copyOwner is a no-op on platforms without Unix file ownership
+func copyOwner(f *os.File, existing fs.FileInfo) error {
+	return nil
+}
@@ -0,0 +1,23 @@
+//go:build unix
+
+package writer
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// copyOwner gives f the owner and group of existing. Without the privileges to
+// change the owner, f keeps the owner of the process and no error is returned.
+func copyOwner(f *os.File, existing fs.FileInfo) error {
+	st, ok := existing.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if err := f.Chown(int(st.Uid), int(st.Gid)); err != nil && !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite_KeepsOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing the owner of a file requires root")
+	}
+
+	path := filepath.Join(t.TempDir(), "redisdb.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0640))
+	require.NoError(t, os.Chown(path, 1234, 5678))
+
+	status, err := Write(path, []byte("new\n"), 0644)
+	require.NoError(t, err)
+	assert.Equal(t, StatusUpdated, status)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	st, ok := info.Sys().(*syscall.Stat_t)
+	require.True(t, ok)
+	assert.Equal(t, uint32(1234), st.Uid)
+	assert.Equal(t, uint32(5678), st.Gid)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
@@ -0,0 +1,119 @@
+package writer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Status describes the outcome of writing an output file
+type Status string
+
+const (
+	// StatusCreated means the file did not exist and was created
+	StatusCreated Status = "created"
+	// StatusUpdated means the file existed and its content was replaced
+	StatusUpdated Status = "updated"
+	// StatusUnchanged means the file already had the same content and was not touched
+	StatusUnchanged Status = "unchanged"
+)
+
+// Write atomically writes content to path.
+// The content is written to a temporary file in the same directory, synced
+// to disk and renamed over path, so readers never observe a partially
+// written file. If the existing file already has the same content hash,
+// nothing is written and its modification time is preserved.
+// perm only applies to new files; an existing file keeps its mode and,
+// where the platform and privileges allow, its owner and group.
+func Write(path string, content []byte, perm os.FileMode) (Status, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory '%s': %w", dir, err)
+	}
+
+	status := StatusUpdated
+	current, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to read existing file '%s': %w", path, err)
+		}
+		status = StatusCreated
+	} else if sameContent(current, content) {
+		return StatusUnchanged, nil
+	}
+
+	// Keep the mode and owner of an existing file
+	var existing fs.FileInfo
+	if status == StatusUpdated {
+		existing, err = os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat existing file '%s': %w", path, err)
+		}
+		perm = existing.Mode().Perm()
+	}
+
+	if err := writeAtomic(path, content, perm, existing); err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// sameContent reports whether two contents have the same SHA-256 hash
+func sameContent(a, b []byte) bool {
+	hashA := sha256.Sum256(a)
+	hashB := sha256.Sum256(b)
+	return bytes.Equal(hashA[:], hashB[:])
+}
+
+// writeAtomic writes content to a temporary file next to path and renames it into place.
+// When existing is set, the temporary file gets its owner before the rename.
+func writeAtomic(path string, content []byte, perm os.FileMode, existing fs.FileInfo) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for '%s': %w", path, err)
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file if anything goes wrong before the rename
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err = tmp.Write(content); err != nil {
+		return fmt.Errorf("failed to write temporary file for '%s': %w", path, err)
+	}
+	if existing != nil {
+		if err = copyOwner(tmp, existing); err != nil {
+			return fmt.Errorf("failed to set owner on temporary file for '%s': %w", path, err)
+		}
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set permissions on temporary file for '%s': %w", path, err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file for '%s': %w", path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file for '%s': %w", path, err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to rename temporary file to '%s': %w", path, err)
+	}
+
+	// Sync the directory so the rename itself is durable
+	if d, dirErr := os.Open(dir); dirErr == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("create new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "conf.d", "redisdb.yaml")
+
+		status, err := Write(path, []byte("init_config:\n"), 0644)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCreated, status)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "init_config:\n", string(content))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	})
+
+	t.Run("update existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+
+		status, err := Write(path, []byte("new\n"), 0644)
+		require.NoError(t, err)
+		assert.Equal(t, StatusUpdated, status)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "new\n", string(content))
+	})
+
+	t.Run("update keeps the mode of the existing file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+		require.NoError(t, os.Chmod(path, 0600))
+
+		status, err := Write(path, []byte("new\n"), 0644)
+		require.NoError(t, err)
+		assert.Equal(t, StatusUpdated, status)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("unchanged file keeps modification time", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "redisdb.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("same\n"), 0644))
+		past := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(path, past, past))
+
+		status, err := Write(path, []byte("same\n"), 0644)
+		require.NoError(t, err)
+		assert.Equal(t, StatusUnchanged, status)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, info.ModTime().Equal(past))
+	})
+
+	t.Run("no temporary files left behind", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "redisdb.yaml")
+
+		_, err := Write(path, []byte("content\n"), 0644)
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "redisdb.yaml", entries[0].Name())
+	})
+
+	t.Run("path is a directory", func(t *testing.T) {
+		_, err := Write(t.TempDir(), []byte("content\n"), 0644)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read existing file")
+	})
+}